@@ -10,11 +10,17 @@ const (
 	APP_CONF_DIR      = "finance-planner"
 	APP_CONF_FILENAME = "conf.json"
 
-	Day     = "Day"
+	// application-wide preferences, such as column layouts, are stored next
+	// to the default config file
+	APP_PREFS_FILENAME = "prefs.json"
+	PrefsSaveDelayMs   = 500
+
+	Daily   = "Daily"
 	Weekly  = "Weekly"
 	Monthly = "Monthly"
 	Yearly  = "Yearly"
 
+	DAILY   = "DAILY"
 	WEEKLY  = "WEEKLY"
 	MONTHLY = "MONTHLY"
 	YEARLY  = "YEARLY"
@@ -22,6 +28,104 @@ const (
 	Y = "Y"
 	W = "W"
 	M = "M"
+	D = "D"
+
+	// monthly rules, e.g. "first Monday" or "last business day"
+	MonthlyRuleDay         = "day"
+	MonthlyRuleBusinessDay = "business day"
+	MonthlyRuleLast        = "last"
+
+	// weekend/holiday policies, i.e. which business day a transaction is
+	// moved to when it falls on a weekend or holiday
+	ShiftPrevious = "previous"
+	ShiftNext     = "next"
+
+	// exceptions, e.g. "2026-11-01 skip; 2026-12-01 $-250.00 to 2026-12-03"
+	ExceptionSkip      = "skip"
+	ExceptionMoveTo    = "to"
+	ExceptionSeparator = ";"
+
+	// words in amount changes and escalation rules, e.g. "$-1900.00 from
+	// 2027-06-01" or "+4% every 12 months from 2027-06-01"
+	AmountScheduleFrom             = "from"
+	AmountScheduleEvery            = "every"
+	AmountScheduleUntil            = "until"
+	AmountScheduleThen             = "then"
+	AmountScheduleEscalationFormat = "%v every %v months from %v"
+	AmountScheduleMonthlyFormat    = "%v every month from %v"
+
+	// AmountVariesFormat is shown in the Amount column for a transaction with
+	// a seasonal amount table, e.g. "varies ($-60.00–$-210.00)"
+	AmountVariesFormat = "varies (%v–%v)"
+
+	// FormulaPrefix starts an amount that's calculated from the plan's
+	// variables, e.g. "=salary*0.1"
+	FormulaPrefix = "="
+
+	// words in sweep rules, e.g. "above $8000.00 to Savings"
+	SweepAbove = "above"
+	SweepBelow = "below"
+	SweepTo    = "to"
+	SweepFrom  = "from"
+
+	// how sweeps are listed in the results' transaction names
+	SweepToNameFormat   = "Sweep to %v (%v)"
+	SweepFromNameFormat = "Top-up from %v (%v)"
+
+	// how often interest compounds; see planner.Interest
+	InterestDaily   = "daily"
+	InterestMonthly = "monthly"
+
+	// how interest settings and posted interest are shown
+	InterestSummaryFormat     = "%v APY, %v APR, compounded %v, posted on day %v"
+	InterestEarnedNameFormat  = "Interest earned (%v)"
+	InterestChargedNameFormat = "Interest charged (%v)"
+
+	// how a loan's schedule is summarized; see planner.Loan
+	LoanSummaryFormat      = "%v a month, paid off on %v after %v payments, with %v of interest"
+	LoanSummaryExtraFormat = "%v (including %v extra)"
+	LoanSummarySavedFormat = "%v, %v payments early"
+
+	// debt payoff strategies; see planner.GetDebtPlan
+	DebtSnowball      = "snowball"
+	DebtAvalanche     = "avalanche"
+	DebtSnowballName  = "Snowball (smallest balance first)"
+	DebtAvalancheName = "Avalanche (highest APR first)"
+
+	// how debt payoff plans are summarized and added
+	DebtPlanSummaryFormat = "%v: debt-free on %v, with %v of interest"
+	DebtPaymentNameFormat = "%v payment"
+	DebtPaymentNoteFormat = "%v payoff plan, with %v of interest"
+
+	// how savings goals are entered, marked and described; see planner.Goal
+	GoalBy            = "by"
+	GoalMarkerFormat  = "%v  ⚑ %v (%v)"
+	GoalOutOfRange    = "its date is outside of the results' date range"
+	GoalOnTrackFormat = "on track, with %v to spare"
+	GoalShortFormat   = "%v short; save %v more a month"
+
+	// how sinking funds are added and shown; see planner.GetSinkingFunds
+	SinkingFundNameFormat           = "Sinking fund: %v"
+	SinkingFundWithdrawalNameFormat = "Sinking fund: %v (withdrawal)"
+	SinkingFundNoteFormat           = "Sets aside %v by %v"
+	SinkingFundBalanceSeparator     = "; "
+
+	// how pay periods are described; see planner.GetPayPeriods
+	PayPeriodNoPayday         = "before the first paycheck"
+	PayPeriodBillFormat       = "%v %v (%v)"
+	PayPeriodBillSeparator    = "; "
+	PayPeriodSuggestionFormat = "pay %v (%v, due %v) from the previous paycheck"
+
+	// words in linked amounts, e.g. "10% of <ID> rounded up to $5.00"
+	LinkFormat    = "%v of %v"
+	LinkOf        = "of"
+	LinkRounded   = "rounded"
+	LinkRoundUp   = "up"
+	LinkRoundDown = "down"
+
+	// LinkedAmountFormat is shown in the Amount column for a transaction
+	// whose amount is linked to another, e.g. "$-200.00 (10% of Paycheck)"
+	LinkedAmountFormat = "%v (%v of %v)"
 
 	New = "New"
 
@@ -34,6 +138,8 @@ const (
 	IconAssetPath = "assets/icon-128.png"
 
 	BalanceInputPlaceholderText = "$500.00 - Enter a balance to start with."
+	MinimumInputPlaceholderText = "$0.00 - Enter a balance to stay above."
+	MinimumInputTooltip         = "The minimum balance, which the safe-to-spend amount in the header bar keeps the projected balance above."
 	FullGridWidth               = 2
 	HalfGridWidth               = 1
 	ScrolledWindowGridHeight    = 4
@@ -45,6 +151,19 @@ const (
 	GtkSignalFocusOut     = "focus-out-event"
 	GtkSignalEditingStart = "editing-started"
 	GtkSignalEdited       = "edited"
+	GtkSignalDragBegin    = "drag-begin"
+	GtkSignalDragEnd      = "drag-end"
+	GtkSignalToggled      = "toggled"
+
+	GtkSignalButtonPressEvent = "button-press-event"
+	GtkSignalColumnsChanged   = "columns-changed"
+	GtkSignalNotifyFixedWidth = "notify::fixed-width"
+	GtkSignalKeyPressEvent    = "key-press-event"
+	GtkSignalValueChanged     = "value-changed"
+	GtkSignalDayDoubleClick   = "day-selected-double-click"
+	GtkSignalQueryTooltip     = "query-tooltip"
+
+	GtkStyleClassLinked = "linked"
 
 	ActionGroupFin = "fin"
 	ActionGroupApp = "app"
@@ -61,33 +180,306 @@ const (
 	ActionLoadConfigNewWindow     = "loadConfigNewWindow"
 	ActionGetStats                = "getStats"
 	ActionAbout                   = "showAboutDialog"
-
-	MenuItemSave          = "Save"
-	MenuItemSaveAs        = "Save as..."
-	MenuItemOpen          = "Open..."
-	MenuItemOpenNewWindow = "Open in new window..."
-	MenuItemSaveResults   = "Save results..."
-	MenuItemCopyResults   = "Copy results to clipboard"
-	MenuItemShowStats     = "Show statistics"
-	MenuItemAbout         = "About"
-	MenuItemNewWindow     = "New Window"
-	MenuItemCloseWindow   = "Close Window"
-	MenuItemQuit          = "Quit"
+	ActionCopyTX                  = "copyTX"
+	ActionCopyTXAsTSV             = "copyTXAsTSV"
+	ActionPasteTX                 = "pasteTX"
+	ActionEditRRule               = "editRRule"
+	ActionEditSeasonal            = "editSeasonal"
+	ActionEditVariables           = "editVariables"
+	ActionEditSweeps              = "editSweeps"
+	ActionEditInterest            = "editInterest"
+	ActionNewLoan                 = "newLoan"
+	ActionEditLoan                = "editLoan"
+	ActionDebtPlanner             = "debtPlanner"
+	ActionEditGoals               = "editGoals"
+	ActionSinkingFunds            = "sinkingFunds"
+	ActionPayPeriods              = "payPeriods"
+	ActionLoadHolidays            = "loadHolidays"
+	ActionDefaultHolidays         = "useDefaultHolidays"
+
+	MenuItemSave            = "Save"
+	MenuItemSaveAs          = "Save as..."
+	MenuItemOpen            = "Open..."
+	MenuItemOpenNewWindow   = "Open in new window..."
+	MenuItemSaveResults     = "Save results..."
+	MenuItemCopyResults     = "Copy results to clipboard"
+	MenuItemShowStats       = "Show statistics"
+	MenuItemCopyTX          = "Copy selected transactions"
+	MenuItemCopyTXAsTSV     = "Copy selected transactions for spreadsheets"
+	MenuItemPasteTX         = "Paste transactions"
+	MenuItemEditRRule       = "Edit recurrence rule..."
+	MenuItemEditSeasonal    = "Edit seasonal amounts..."
+	MenuItemEditVariables   = "Variables..."
+	MenuItemEditSweeps      = "Sweep rules..."
+	MenuItemEditInterest    = "Interest..."
+	MenuItemNewLoan         = "New loan..."
+	MenuItemEditLoan        = "Loan schedule..."
+	MenuItemDebtPlanner     = "Debt payoff planner..."
+	MenuItemEditGoals       = "Savings goals..."
+	MenuItemSinkingFunds    = "Sinking funds..."
+	MenuItemPayPeriods      = "Pay periods..."
+	MenuItemLoadHolidays    = "Load holiday calendar..."
+	MenuItemDefaultHolidays = "Use US federal holidays"
+	MenuItemAbout           = "About"
+	MenuItemNewWindow       = "New Window"
+	MenuItemCloseWindow     = "Close Window"
+	MenuItemQuit            = "Quit"
 
 	HideInactiveBtnLabel = "_Hide inactive"
 	CloneBtnLabel        = "_Clone"
 	AddBtnLabel          = "_+"
 	DelBtnLabel          = "_-"
+	ManualOrderBtnLabel  = "_Manual order"
 	ConfigTabLabel       = "Config"
+	DetailBtnLabel       = "D_etails"
+
+	// transaction detail panel
+	DetailPanelWidth          = 320
+	DetailNoteHeight          = 100
+	DetailOccurrencesCount    = 10
+	DetailNothingSelected     = "Select a single transaction to edit it here."
+	DetailDatePickerTooltip   = "Double-click a day to choose it."
+	DetailDatePlaceholder     = "YYYY-MM-DD"
+	DetailNoOccurrences       = "No occurrences in the projection window."
+	DetailInactiveOccurrences = "(inactive - not included in the results)"
+	DetailLabelName           = "Name"
+	DetailLabelAmount         = "Amount"
+	DetailAmountTooltip       = "An expense unless it starts with a \"+\". It can also be a formula that uses the plan's variables, such as \"=salary*0.1\"."
+	DetailLabelAmounts        = "Changes"
+	DetailAmountsPlaceholder  = "none, e.g. +3% every year from 2027-01-01"
+	DetailAmountsTooltip      = "Change the amount over time, either with dated amounts separated by semicolons, such as \"$-1900.00 from 2027-06-01\", or with an escalation rule, such as \"+4% every 12 months from 2027-06-01\"."
+	DetailLabelSeasonal       = "Seasonal"
+	DetailSeasonalEditLabel   = "Edit..."
+	DetailSeasonalPlaceholder = "none (same every month)"
+	DetailLabelLink           = "Linked to"
+	DetailLinkPlaceholder     = "none, e.g. 10% of Paycheck"
+	DetailLinkTooltip         = "Make the amount a percentage of another transaction's amount, which is referred to by its name or its ID, such as \"10% of Paycheck rounded up to $1\". It's an expense unless the percentage starts with a \"+\"."
+	DetailLabelActive         = "Active"
+	DetailLabelFrequency      = "Frequency"
+	DetailLabelInterval       = "Every"
+	DetailLabelMonthlyRule    = "Monthly on"
+	DetailMonthlyRuleSameDate = "same date"
+	DetailMonthlyRuleTooltip  = "Which day of the month a monthly transaction occurs on. By default, it's the same day of the month as the start date."
+	DetailLabelWeekdays       = "Weekdays"
+	DetailLabelStarts         = "Starts"
+	DetailLabelEnds           = "Ends"
+	DetailLabelRRule          = "Rule"
+	DetailRRuleEditLabel      = "Edit..."
+	DetailRRulePlaceholder    = "none (iCalendar RRULE)"
+	DetailLabelShift          = "Weekends/holidays"
+	DetailShiftNone           = "don't move"
+	DetailShiftTooltip        = "Banks don't post on weekends or holidays, so this can be moved to the business day before or after."
+	DetailLabelExceptions     = "Exceptions"
+	DetailExceptionsAdd       = "Add..."
+	DetailExceptionsTooltip   = "Skip, change the amount of, or move single occurrences. Occurrences can also be changed by right-clicking a day in the results."
+	DetailLabelNote           = "Note"
+	DetailLabelID             = "ID"
+	DetailLabelCreatedAt      = "Created"
+	DetailLabelUpdatedAt      = "Updated"
+	DetailLabelOccurrences    = "Next occurrences"
+	DetailDateFormat          = "Mon 2006-01-02"
+	DetailTimestampFormat     = "2006-01-02 15:04:05"
 
 	// user-facing messages
 	MsgInvalidDateInput          = "Enter a valid date in the format YYYY-MM-DD."
 	MsgStartBalanceCannotBeEmpty = "Enter a non-empty currency-like value for the starting balance."
-	MsgInvalidRecurrence         = "Please enter one of the following values: y/m/w/monthly/weekly/yearly"
+	MsgInvalidRecurrence         = "Please enter one of the following values: d/w/m/y/daily/weekly/monthly/yearly"
+	MsgDailyWeekdays             = "weekdays only apply to weekly transactions, since daily ones occur every Interval days"
+	MsgNothingSelectedToCopy     = "Select one or more transactions to copy first."
+	MsgRRuleHint                 = "A recurrence rule needs an RRULE or RDATE, such as FREQ=MONTHLY;BYMONTHDAY=15"
+	MsgSelectOneToEditRRule      = "Select a single transaction to edit its recurrence rule."
+	MsgSelectOneToEditLoan       = "Select a single transaction that was created with New loan... to see its schedule."
+	MsgSelectOneToEditSeasonal   = "Select a single transaction to edit its seasonal amounts."
+	MsgAmountScheduleHint        = "enter amount changes like \"$-1900.00 from 2027-06-01\" separated by semicolons, or the amounts in effect until a date like \"$1,800 until 2027-05-31, then $1,900\", or an escalation rule like \"+4% every 12 months from 2027-06-01\" or \"+$50 every year from 2027-01-01\""
+	MsgSeasonalAmountsHint       = "enter twelve amounts from January to December separated by semicolons, such as \"Jan $-180; Feb $-160; ...\", or leave it empty to use the fixed amount"
+	MsgLinkHint                  = "enter something like \"10% of Paycheck\" (or the paycheck's ID), \"+25% of Paycheck\" for income, or \"30% of Paycheck rounded up to $5\", or leave it empty to use the fixed amount"
+	MsgFormulaHint               = "a formula such as \"=salary*0.1\" or \"=1200/12\" can use +, -, *, /, parentheses and the variables from Variables... in the menu, and it's an expense unless it starts with \"=+\""
+	MsgDebtsHint                 = "enter one debt per line, with its name, balance, APR and minimum payment separated by semicolons, such as \"Visa; $4,500; 24.99%; $90\""
+	MsgGoalsHint                 = "enter one goal per line, such as \"$12,000 Emergency fund by 2027-06-01\""
+	MsgSweepHint                 = "enter one rule per line, such as \"above $8,000 to Savings\" or \"below $1,000 from Savings\""
+	MsgVariablesHint             = "enter one variable per line, such as \"salary = 5200\""
+	MsgExceptionHint             = "enter something like \"2026-11-01 skip\", \"2026-12-01 $-250.00\" (or \"+$20\" for income) or \"2026-12-01 to 2026-12-03\", separated by semicolons"
+	MsgShiftHint                 = "enter \"previous\" or \"next\" to move it to the previous or next business day, or leave it empty to not move it"
+	MsgHolidaysLoaded            = "Loaded %v holidays from \"%v\". Transactions are now moved off of these days instead of the US federal holidays."
+	MsgHolidaysDefault           = "Transactions are now moved off of the built-in US federal holidays."
+	MsgHolidaysLoadFailed        = "Failed to load the holiday calendar, so the US federal holidays are used instead: %v"
+	MsgMonthlyRuleHint           = "enter something like \"first Monday\", \"last day\" or \"last business day\", or leave it empty to use the start date's day of the month"
+
+	// validation
+	ProblemCellBackground = "rgba(224, 27, 36, 0.25)"
+	ProblemsLabelSingular = "1 problem"
+	ProblemsLabelPlural   = "%v problems"
+
+	// safe-to-spend indicator; see planner.GetSafeToSpend
+	SafeToSpendFormat            = "Safe to spend: %v"
+	SafeToSpendBalanceFormat     = "Balance on %v: %v"
+	SafeToSpendLowestFormat      = "Lowest projected balance: %v on %v (a drawdown of %v)"
+	SafeToSpendMinimumFormat     = "Minimum balance: %v"
+	SafeToSpendBelowFormat       = "The balance falls %v below the minimum on %v, so nothing is safe to spend."
+	SafeToSpendConstrainedFormat = "Spending more than this would take the balance below the minimum on %v."
+	SafeToSpendToday             = "Spending more than this would take today's balance below the minimum."
+
+	// recurrence rule editor
+	RRuleEditorTitle       = "Recurrence rule"
+	RRuleEditorWidth       = 480
+	RRuleEditorHeight      = 100
+	RRuleEditorPreviewSize = 10
+	RRuleEditorHelp        = "Enter an iCalendar (RFC 5545) recurrence rule, such as FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15 or FREQ=MONTHLY;BYDAY=2TH,4TH. It replaces the frequency, interval, weekdays and monthly rule, but the start and end dates still apply. Leave it empty to go back to those."
+	RRuleEditorValid       = "Next %v dates:"
+	RRuleEditorEmpty       = "No rule - the frequency, interval and weekdays are used."
+	RRuleEditorNoDates     = "This rule never occurs."
+	RRuleEditorApply       = "_Apply"
+	RRuleEditorCancel      = "_Cancel"
+
+	// sweep rules editor
+	SweepsEditorTitle  = "Sweep rules"
+	SweepsEditorWidth  = 420
+	SweepsEditorHeight = 160
+	SweepsEditorHelp   = "Enter one rule per line. \"above $8,000 to Savings\" moves everything above $8,000 to savings, and \"below $1,000 from Savings\" tops the balance back up to $1,000 from savings. The rules are checked in order at the end of every day, and each sweep is listed in that day's results. Lines starting with # are ignored."
+	SweepsEditorValid  = "%v rules."
+	SweepsEditorApply  = "_Apply"
+	SweepsEditorCancel = "_Cancel"
+
+	// loan editor
+	LoanEditorNewTitle             = "New loan"
+	LoanEditorTitle                = "Loan"
+	LoanEditorWidth                = 560
+	LoanEditorHeight               = 560
+	LoanEditorHelp                 = "Enter the loan's details to create its monthly payment, which ends on the day the loan is paid off. The payment and schedule below are updated as you type, so try different extra payments to see how much sooner the loan is paid off."
+	LoanEditorName                 = "Name"
+	LoanEditorPrincipal            = "Principal"
+	LoanEditorPrincipalPlaceholder = "$25,000.00"
+	LoanEditorRate                 = "Interest rate (APR %)"
+	LoanEditorTerm                 = "Term (months)"
+	LoanEditorStart                = "First payment (YYYY-MM-DD)"
+	LoanEditorExtra                = "Extra payment each month"
+	LoanEditorDefaultName          = "Loan"
+	LoanEditorDefaultRate          = 6.5
+	LoanEditorDefaultTerm          = 60
+	LoanEditorMaxTerm              = 600
+	LoanEditorAdd                  = "_Add"
+	LoanEditorApply                = "_Apply"
+	LoanEditorCancel               = "_Cancel"
+
+	// savings goals editor
+	GoalsEditorTitle       = "Savings goals"
+	GoalsEditorWidth       = 640
+	GoalsEditorHeight      = 420
+	GoalsEditorInputHeight = 100
+	GoalsEditorHelp        = "Enter one goal per line, such as \"$12,000 Emergency fund by 2027-06-01\". Each goal is checked against the projected balance on its date, and marked with ⚑ in the results' Balance column. Lines starting with # are ignored."
+	GoalsEditorSummary     = "%v of %v goals are on track."
+	GoalsEditorApply       = "_Apply"
+	GoalsEditorCancel      = "_Cancel"
+
+	// sinking fund calculator
+	SinkingFundsTitle     = "Sinking funds"
+	SinkingFundsWidth     = 720
+	SinkingFundsHeight    = 420
+	SinkingFundsHelp      = "Bills that don't recur every month, such as a yearly insurance premium, can be paid for by setting aside the same amount every month. Adding the transfers adds a monthly transfer into a sinking fund for each bill, and a withdrawal from the fund whenever the bill is due, so that only the transfers come out of the balance. Each fund's balance is shown in the results' SinkingFunds column."
+	SinkingFundsStart     = "First transfer (YYYY-MM-DD)"
+	SinkingFundsSummary   = "Setting aside %v a month covers %v bills by their next due dates."
+	SinkingFundsNone      = "There are no bills that recur less often than every month."
+	SinkingFundsFunded    = "already funded"
+	SinkingFundsNotFunded = "not funded"
+	SinkingFundsAdd       = "_Add transfers"
+	SinkingFundsCancel    = "_Cancel"
+
+	// pay periods view
+	PayPeriodsTitle    = "Pay periods"
+	PayPeriodsWidth    = 900
+	PayPeriodsHeight   = 480
+	PayPeriodsHelp     = "The results are split into periods from each paycheck until the day before the next one, showing the bills that are due before the next paycheck and what's left of the period's income after them. Periods that run short are highlighted."
+	PayPeriodsIncome   = "Paycheck"
+	PayPeriodsSummary  = "%v of %v pay periods run short."
+	PayPeriodsNoIncome = "There are no active income transactions to split the results by."
+	PayPeriodsClose    = "_Close"
+
+	// debt payoff planner
+	DebtPlannerTitle             = "Debt payoff planner"
+	DebtPlannerWidth             = 640
+	DebtPlannerHeight            = 560
+	DebtPlannerDebtsHeight       = 120
+	DebtPlannerHelp              = "Enter one debt per line, with its name, balance, APR and minimum payment separated by semicolons, such as \"Visa; $4,500; 24.99%; $90\". Every month, the minimum payments are made and the rest of the budget goes to one debt at a time: the smallest balance first with the snowball, or the highest APR first with the avalanche. Once a debt is paid off, its payment rolls over to the next one."
+	DebtPlannerBudget            = "Monthly budget for debts"
+	DebtPlannerBudgetPlaceholder = "$1,000.00"
+	DebtPlannerStart             = "First payment (YYYY-MM-DD)"
+	DebtPlannerStrategy          = "Strategy"
+	DebtPlannerAdd               = "_Add payments"
+	DebtPlannerCancel            = "_Cancel"
+
+	// interest editor
+	InterestEditorTitle       = "Interest"
+	InterestEditorHelp        = "Interest accrues every day on the balance at the end of the day, and everything that has accrued is posted on the posting day of each month, which shows up in the results as its own line item. The savings rate is the APY that's earned while the balance is positive, and the debt rate is the APR that's charged while it's negative, such as on a carried credit card balance."
+	InterestEditorSavingsRate = "Savings rate (APY %)"
+	InterestEditorDebtRate    = "Debt rate (APR %)"
+	InterestEditorCompounding = "Compounding"
+	InterestEditorDaily       = "Daily"
+	InterestEditorMonthly     = "Monthly (average daily balance)"
+	InterestEditorPostingDay  = "Posting day of the month"
+	InterestEditorApply       = "_Apply"
+	InterestEditorRemove      = "_Turn off"
+	InterestEditorCancel      = "_Cancel"
+
+	// variables editor
+	VariablesEditorTitle  = "Variables"
+	VariablesEditorWidth  = 420
+	VariablesEditorHeight = 200
+	VariablesEditorHelp   = "Enter one variable per line, such as \"salary = 5200\" or \"rent = 1850\"; a value can also use the variables above it, such as \"half = salary/2\". Names can only have the letters A-Z, digits and underscores. Amounts can then be formulas that use them, such as \"=salary*0.1\" or \"=rent/2\", and they're recalculated whenever the variables change. Lines starting with # are ignored."
+	VariablesEditorValid  = "%v variables, used by %v transactions."
+	VariablesEditorApply  = "_Apply"
+	VariablesEditorCancel = "_Cancel"
+
+	// seasonal amounts editor
+	SeasonalEditorTitle      = "Seasonal amounts"
+	SeasonalEditorColumns    = 3
+	SeasonalEditorEntryWidth = 10
+	SeasonalEditorHelp       = "Enter the amount for each month. As with the Amount column, amounts are expenses unless they start with a \"+\". Remove the table to go back to the fixed amount."
+	SeasonalEditorSummary    = "Ranges from %v to %v, %v over a year of monthly occurrences."
+	SeasonalEditorApply      = "_Apply"
+	SeasonalEditorRemove     = "_Use fixed amount"
+	SeasonalEditorCancel     = "_Cancel"
+
+	// occurrence exception editor
+	ExceptionEditorTitle     = "Change an occurrence"
+	ExceptionEditorWidth     = 420
+	ExceptionEditorHelp      = "Skip, change the amount of, or move a single occurrence of this transaction. Enter the date that it's scheduled for, before it's moved off of a weekend or holiday."
+	ExceptionEditorDate      = "Scheduled for"
+	ExceptionEditorSkip      = "Skip this occurrence"
+	ExceptionEditorAmount    = "Amount"
+	ExceptionEditorMoveTo    = "Move to"
+	ExceptionEditorUnchanged = "unchanged (%v)"
+	ExceptionEditorApply     = "_Apply"
+	ExceptionEditorRemove    = "_Remove"
+	ExceptionEditorCancel    = "_Cancel"
+
+	// results row menu
+	ResultsMenuNothing = "No transactions on %v"
+	ResultsMenuSkipped = "skipped"
+	ResultsMenuSkip    = "Skip this occurrence"
+	ResultsMenuRestore = "Restore this occurrence"
+	ResultsMenuEdit    = "Change this occurrence..."
+	ResultsMenuUndo    = "Undo changes to this occurrence"
+
+	// notes on occurrences that differ from their transaction's schedule,
+	// e.g. "Rent (moved from Sun 2026-11-01)"
+	OccurrenceNoteFormat    = "%v (%v)"
+	OccurrenceMovedFrom     = "moved from %v"
+	OccurrenceAmountChanged = "%v instead of %v"
+
+	// holiday calendar file chooser
+	HolidaysFileChooserTitle  = "Load holiday calendar"
+	HolidaysFileChooserLoad   = "_Load"
+	HolidaysFileChooserCancel = "_Cancel"
+
+	// recurring totals footer
+	TotalsLabelFormat  = "Per month: %v income, %v expenses, %v net    Per year: %v income, %v expenses, %v net"
+	TotalsLabelTooltip = "Recurring totals of the active transactions shown above, over an average month and year."
 
 	// error codes - generate new ones with "uuidgen | cut -b 1-6"
 	ErrorCodeSyncConfigListStore                      = "9a0fab"
 	ErrorCodeSyncConfigListStoreAfterColumnSortChange = "a6bbb2"
+	ErrorCodeSyncConfigListStoreAfterReorder          = "5d1c7e"
+	ErrorCodeSyncConfigListStoreAfterPaste            = "c42e09"
 )
 
 const (
@@ -117,6 +509,7 @@ const (
 	ColumnDayNet              = "DayNet"
 	ColumnDiffFromStart       = "DiffFromStart"
 	ColumnDayTransactionNames = "DayTransactionNames"
+	ColumnSinkingFunds        = "SinkingFunds"
 )
 
 const (
@@ -129,6 +522,7 @@ const (
 	ColumnDayNetIndex
 	ColumnDiffFromStartIndex
 	ColumnDayTransactionNamesIndex
+	ColumnSinkingFundsIndex
 )
 
 var ResultsColumns = []string{
@@ -141,8 +535,24 @@ var ResultsColumns = []string{
 	ColumnDayNet,
 	ColumnDiffFromStart,
 	ColumnDayTransactionNames,
+	ColumnSinkingFunds,
 }
 
+// LoanScheduleColumns are the columns of a loan's amortization schedule.
+var LoanScheduleColumns = []string{"#", "Date", "Payment", "Interest", "Principal", "Balance"}
+
+// GoalColumns are the columns of the savings goals' statuses.
+var GoalColumns = []string{"Goal", "Target", "By", "Projected", "Status"}
+
+// SinkingFundColumns are the columns of the sinking fund calculator.
+var SinkingFundColumns = []string{"Bill", "Next due", "Amount", "Months", "Monthly", "Then monthly", "Status"}
+
+// PayPeriodColumns are the columns of the pay periods view.
+var PayPeriodColumns = []string{"Payday", "Until", "Income", "Bills", "Committed", "Left", "Balance", "Suggestion"}
+
+// DebtPlanColumns are the columns of a debt payoff plan.
+var DebtPlanColumns = []string{"Debt", "Balance", "APR", "Paid off", "Payments", "Interest"}
+
 // make ResultsColumnsIndexes the same length as the "columns" variable
 var ResultsColumnsIndexes = []int{
 	ColumnDateIndex,
@@ -154,31 +564,47 @@ var ResultsColumnsIndexes = []int{
 	ColumnDayNetIndex,
 	ColumnDiffFromStartIndex,
 	ColumnDayTransactionNamesIndex,
+	ColumnSinkingFundsIndex,
 }
 
 // values for the config page
 
 const (
-	// ColumnOrder     = "Order"
-
-	ColumnAmount    = "Amount"    // int in cents; 500 = $5.00
-	ColumnActive    = "Active"    // bool true/false
-	ColumnName      = "Name"      // editable string
-	ColumnFrequency = "Frequency" // dropdown, monthly/daily/weekly/yearly
-	ColumnInterval  = "Interval"  // integer, occurs every x frequency
-	ColumnMonday    = "Monday"    // bool
-	ColumnTuesday   = "Tuesday"   // bool
-	ColumnWednesday = "Wednesday" // bool
-	ColumnThursday  = "Thursday"  // bool
-	ColumnFriday    = "Friday"    // bool
-	ColumnSaturday  = "Saturday"  // bool
-	ColumnSunday    = "Sunday"    // bool
-	ColumnStarts    = "Starts"    // string
-	ColumnEnds      = "Ends"      // string
-	ColumnNote      = "Note"      // editable string
-	ColumnID        = "ID"
-	ColumnCreatedAt = "CreatedAt"
-	ColumnUpdatedAt = "UpdatedAt"
+	ColumnOrder       = "Order"             // int, manual ordering
+	ColumnAmount      = "Amount"            // int in cents; 500 = $5.00
+	ColumnAmounts     = "Amount changes"    // string, e.g. "+4% every 12 months from 2027-06-01"
+	ColumnSeasonal    = "Seasonal amounts"  // string, e.g. "Jan $-180.00; Feb $-160.00; ..."
+	ColumnLink        = "Linked to"         // string, e.g. "10% of <ID> rounded to $1.00"
+	ColumnActive      = "Active"            // bool true/false
+	ColumnName        = "Name"              // editable string
+	ColumnFrequency   = "Frequency"         // dropdown, monthly/daily/weekly/yearly
+	ColumnInterval    = "Interval"          // integer, occurs every x frequency
+	ColumnMonthlyRule = "Monthly on"        // string, e.g. "last business day"
+	ColumnMonday      = "Monday"            // bool
+	ColumnTuesday     = "Tuesday"           // bool
+	ColumnWednesday   = "Wednesday"         // bool
+	ColumnThursday    = "Thursday"          // bool
+	ColumnFriday      = "Friday"            // bool
+	ColumnSaturday    = "Saturday"          // bool
+	ColumnSunday      = "Sunday"            // bool
+	ColumnStarts      = "Starts"            // string
+	ColumnEnds        = "Ends"              // string
+	ColumnRRule       = "RRule"             // string, overrides the recurrence columns
+	ColumnShift       = "Weekends/holidays" // string, e.g. "next business day"
+	ColumnExceptions  = "Exceptions"        // string, e.g. "2026-11-01 skip"
+	ColumnNote        = "Note"              // editable string
+	ColumnID          = "ID"
+	ColumnCreatedAt   = "CreatedAt"
+	ColumnUpdatedAt   = "UpdatedAt"
+
+	// ColumnOccurrences is computed rather than stored; it's the number of
+	// times the TX occurs between the results' start and end dates
+	ColumnOccurrences = "Occurrences in range"
+
+	// ColumnYearly and ColumnMonthly are computed; they're what the TX adds
+	// up to over an average year or month
+	ColumnYearly  = "Per year"
+	ColumnMonthly = "Per month"
 
 	WeekdayMonday    = "Monday"
 	WeekdayTuesday   = "Tuesday"
@@ -190,27 +616,62 @@ const (
 )
 
 var ConfigColumns = []string{
-	// ColumnOrder,     // int
-	ColumnAmount,    // int in cents; 500 = $5.00
-	ColumnActive,    // bool true/false
-	ColumnName,      // editable string
-	ColumnFrequency, // dropdown, monthly/daily/weekly/yearly
-	ColumnInterval,  // integer, occurs every x frequency
-	ColumnMonday,    // bool
-	ColumnTuesday,   // bool
-	ColumnWednesday, // bool
-	ColumnThursday,  // bool
-	ColumnFriday,    // bool
-	ColumnSaturday,  // bool
-	ColumnSunday,    // bool
-	ColumnStarts,    // string
-	ColumnEnds,      // string
-	ColumnNote,      // editable string
+	ColumnOrder,       // int
+	ColumnAmount,      // int in cents; 500 = $5.00
+	ColumnAmounts,     // string, e.g. "+4% every 12 months from 2027-06-01"
+	ColumnSeasonal,    // string, e.g. "Jan $-180.00; Feb $-160.00; ..."
+	ColumnLink,        // string, e.g. "10% of <ID> rounded to $1.00"
+	ColumnActive,      // bool true/false
+	ColumnName,        // editable string
+	ColumnFrequency,   // dropdown, monthly/daily/weekly/yearly
+	ColumnInterval,    // integer, occurs every x frequency
+	ColumnMonthlyRule, // string, e.g. "last business day"
+	ColumnMonday,      // bool
+	ColumnTuesday,     // bool
+	ColumnWednesday,   // bool
+	ColumnThursday,    // bool
+	ColumnFriday,      // bool
+	ColumnSaturday,    // bool
+	ColumnSunday,      // bool
+	ColumnStarts,      // string
+	ColumnEnds,        // string
+	ColumnRRule,       // string, overrides the recurrence columns
+	ColumnShift,       // string, e.g. "next business day"
+	ColumnExceptions,  // string, e.g. "2026-11-01 skip"
+	ColumnNote,        // editable string
 	ColumnID,
 	ColumnCreatedAt,
 	ColumnUpdatedAt,
+	ColumnOccurrences,
+	ColumnYearly,
+	ColumnMonthly,
+}
+
+// ComputedConfigColumns are config columns that are derived from the rest of
+// the TX (and the results' date range), so they can't be edited, pasted, or
+// exported.
+var ComputedConfigColumns = map[string]bool{
+	ColumnOccurrences: true,
+	ColumnYearly:      true,
+	ColumnMonthly:     true,
 }
 
+// Frequencies are the values that the Frequency column accepts, in the order
+// they're offered in pickers (from most to least frequent).
+var Frequencies = []string{
+	DAILY,
+	WEEKLY,
+	MONTHLY,
+	YEARLY,
+}
+
+// Shifts are the values that the weekend/holiday policy accepts, where an
+// empty string means that the transaction isn't moved.
+var Shifts = []string{"", ShiftPrevious, ShiftNext}
+
+// MonthlyRuleOrdinals are the words for the 1st to 4th in a monthly rule.
+var MonthlyRuleOrdinals = []string{"first", "second", "third", "fourth"}
+
 var Weekdays = []string{
 	WeekdayMonday,
 	WeekdayTuesday,
@@ -232,25 +693,40 @@ const (
 )
 
 const (
-	// COLUMN_ORDER     = iota // int
-	COLUMN_AMOUNT    = iota // int in cents; 500 = $5.00
-	COLUMN_ACTIVE           // bool true/false
-	COLUMN_NAME             // editable string
-	COLUMN_FREQUENCY        // dropdown, monthly/daily/weekly/yearly
-	COLUMN_INTERVAL         // integer, occurs every x frequency
-	COLUMN_MONDAY           // bool
-	COLUMN_TUESDAY          // bool
-	COLUMN_WEDNESDAY        // bool
-	COLUMN_THURSDAY         // bool
-	COLUMN_FRIDAY           // bool
-	COLUMN_SATURDAY         // bool
-	COLUMN_SUNDAY           // bool
-	COLUMN_STARTS           // string
-	COLUMN_ENDS             // string
-	COLUMN_NOTE             // editable string
-	COLUMN_ID               // non-editable strings
-	COLUMN_CREATEDAT        // non-editable strings
-	COLUMN_UPDATEDAT        // non-editable strings
+	COLUMN_ORDER       = iota // int
+	COLUMN_AMOUNT             // int in cents; 500 = $5.00
+	COLUMN_AMOUNTS            // string, e.g. "+4% every 12 months from 2027-06-01"
+	COLUMN_SEASONAL           // string, e.g. "Jan $-180.00; Feb $-160.00; ..."
+	COLUMN_LINK               // string, e.g. "10% of <ID> rounded to $1.00"
+	COLUMN_ACTIVE             // bool true/false
+	COLUMN_NAME               // editable string
+	COLUMN_FREQUENCY          // dropdown, monthly/daily/weekly/yearly
+	COLUMN_INTERVAL           // integer, occurs every x frequency
+	COLUMN_MONTHLYRULE        // string, e.g. "last business day"
+	COLUMN_MONDAY             // bool
+	COLUMN_TUESDAY            // bool
+	COLUMN_WEDNESDAY          // bool
+	COLUMN_THURSDAY           // bool
+	COLUMN_FRIDAY             // bool
+	COLUMN_SATURDAY           // bool
+	COLUMN_SUNDAY             // bool
+	COLUMN_STARTS             // string
+	COLUMN_ENDS               // string
+	COLUMN_RRULE              // string, overrides the recurrence columns
+	COLUMN_SHIFT              // string, e.g. "next business day"
+	COLUMN_EXCEPTIONS         // string, e.g. "2026-11-01 skip"
+	COLUMN_NOTE               // editable string
+	COLUMN_ID                 // non-editable strings
+	COLUMN_CREATEDAT          // non-editable strings
+	COLUMN_UPDATEDAT          // non-editable strings
+	COLUMN_OCCURRENCES        // computed int
+	COLUMN_YEARLY             // computed string
+	COLUMN_MONTHLY            // computed string
+
+	// COLUMN_PROBLEMS is the first of a set of hidden bool columns, one per
+	// column above (in the same order), which are true when that cell has a
+	// validation problem; e.g. COLUMN_PROBLEMS+COLUMN_ENDS
+	COLUMN_PROBLEMS
 )
 
 const (
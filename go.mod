@@ -7,10 +7,8 @@ require (
 	github.com/charles-m-knox/finance-planner-lib v0.0.1
 	github.com/charles-m-knox/go-uuid v0.0.2
 	github.com/gotk3/gotk3 v0.6.5-0.20240618185848-ff349ae13f56
+	github.com/teambition/rrule-go v1.8.2
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/teambition/rrule-go v1.8.2 // indirect
-	golang.org/x/sys v0.22.0 // indirect
-)
+require golang.org/x/sys v0.22.0 // indirect
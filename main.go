@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/prefs"
 	"github.com/charles-m-knox/gtk-finance-planner/state"
 	"github.com/charles-m-knox/gtk-finance-planner/ui"
 
@@ -22,6 +24,10 @@ import (
 // // go : embed assets/*.png
 // var embeddedIconFS embed.FS
 
+// prefsFileName is where application-wide preferences (such as column layouts)
+// are stored. Every window reads it when it is created.
+var prefsFileName string
+
 func main() {
 	application, err := gtk.ApplicationNew(constants.GtkAppID, glib.APPLICATION_FLAGS_NONE)
 	if err != nil {
@@ -58,6 +64,10 @@ func main() {
 		}
 	}
 
+	if defaultConfigFile != "" {
+		prefsFileName = path.Join(path.Dir(defaultConfigFile), constants.APP_PREFS_FILENAME)
+	}
+
 	// if defaultConfigFile != "" {
 	// 	bac, err := os.ReadFile(defaultConfigFile)
 	// 	if err != nil {
@@ -113,16 +123,16 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	now := time.Now()
 
 	ws = &state.WinState{
-		HideInactive:     false,
-		ConfigColumnSort: constants.None,
-		OpenFileName:     filename,
-		StartingBalance:  50000,
-		StartDate:        lib.GetNowDateString(now),
-		EndDate:          lib.GetDefaultEndDateString(now),
-		SelectedConfIDs:  make(map[string]bool),
-		TX:               &[]lib.TX{},
-		Results:          &[]lib.Result{},
-		App:              application,
+		HideInactive:    false,
+		ConfigSort:      []planner.SortKey{},
+		OpenFileName:    filename,
+		StartingBalance: 50000,
+		StartDate:       lib.GetNowDateString(now),
+		EndDate:         lib.GetDefaultEndDateString(now),
+		SelectedConfIDs: make(map[string]bool),
+		TX:              &[]planner.TX{},
+		Results:         &[]lib.Result{},
+		App:             application,
 	}
 
 	// the shared function ShowMessageDialog should be initialized first,
@@ -146,6 +156,12 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 
 	ws.ShowMessageDialog = &showMessageDialog
 
+	ws.PrefsFileName = prefsFileName
+	ws.Prefs, err = prefs.Load(prefsFileName)
+	if err != nil {
+		log.Printf("failed to load preferences, defaults will be used: %v", err.Error())
+	}
+
 	// initialize some values
 	ws.ResultsListStore, err = ui.GetNewResultsListStore()
 	if err != nil {
@@ -168,9 +184,9 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 		)
 	}
 
-	saveConfAsFn := func() { ui.SaveConfAs(ws.Win, ws.Header, &ws.OpenFileName, ws.TX) }
+	saveConfAsFn := func() { ui.SaveConfAs(ws) }
 
-	saveOpenConfFn := func() { ui.SaveOpenConf(ws.Win, ws.Header, &ws.OpenFileName, ws.TX) }
+	saveOpenConfFn := func() { ui.SaveOpenConf(ws) }
 
 	saveResultsFn := func() { ui.SaveResults(ws.Win, ws.Header, ws.Results) }
 
@@ -182,6 +198,26 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 
 	cloneConfItemHandler := func() { ui.CloneConfItem(ws) }
 
+	copyConfItemsHandler := func() { ui.CopyConfItems(ws, false) }
+	copyConfItemsAsTSVHandler := func() { ui.CopyConfItems(ws, true) }
+	pasteConfItemsHandler := func() { ui.PasteConfItems(ws) }
+	editRRuleHandler := func() { ui.EditSelectedRRule(ws) }
+	editSeasonalHandler := func() { ui.EditSelectedSeasonalAmounts(ws) }
+	editVariablesHandler := func() { ui.ShowVariablesEditor(ws) }
+	editSweepsHandler := func() { ui.ShowSweepsEditor(ws) }
+	editInterestHandler := func() { ui.ShowInterestEditor(ws) }
+	newLoanHandler := func() { ui.ShowLoanEditor(ws, "") }
+	editLoanHandler := func() { ui.EditSelectedLoan(ws) }
+	debtPlannerHandler := func() { ui.ShowDebtPlanner(ws) }
+	editGoalsHandler := func() { ui.ShowGoalsEditor(ws) }
+	sinkingFundsHandler := func() { ui.ShowSinkingFundCalculator(ws) }
+	payPeriodsHandler := func() { ui.ShowPayPeriods(ws) }
+	loadHolidaysHandler := func() { ui.LoadHolidays(ws) }
+	defaultHolidaysHandler := func() { ui.UseDefaultHolidays(ws) }
+
+	moveConfItemsUp := func() { ui.MoveConfItems(ws, -1) }
+	moveConfItemsDown := func() { ui.MoveConfItems(ws, 1) }
+
 	loadConfCurrentWindowFn := func() { ui.LoadConfig(ws, primary, false) }
 	loadConfNewWindowFn := func() { ui.LoadConfig(ws, primary, true) }
 
@@ -215,7 +251,7 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	ws.Win = win
 	ws.Header = header
 
-	ui.ProcessInitialConfigLoad(ws.Win, &(ws.OpenFileName), ws.TX)
+	ui.ProcessInitialConfigLoad(ws)
 
 	nb, grid := ui.GetStructuralComponents(ws)
 	ws.Notebook = nb
@@ -227,11 +263,18 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	ws.Header.SetTitle(constants.FinancialPlanner)
 	ws.Header.SetSubtitle(ws.OpenFileName)
 	ws.Header.SetShowCloseButton(true)
+	ws.Header.PackEnd(ui.GetProblemsLabel(ws))
+	ws.Header.PackEnd(ui.GetSafeToSpendLabel(ws))
 	mbtn.SetMenuModel(&menu.MenuModel)
 
 	startingBalanceInput, stDateInput, endDateInput := ui.GetResultsInputs(ws)
+	minimumBalanceInput := ui.GetMinimumBalanceInput(ws)
 	addConfItemBtn, delConfItemBtn, cloneConfItemBtn := ui.GetConfEditButtons(ws)
 	hideInactiveCheckbox := ui.GetHideInactiveCheckbox(ws)
+	manualOrderBtn := ui.GetManualOrderButton(ws)
+	detailPanelBtn := ui.GetDetailPanelButton(ws)
+
+	toggleDetailPanel := func() { detailPanelBtn.SetActive(!detailPanelBtn.GetActive()) }
 
 	// all graphical components have been instantiated now - the next part
 	// is to connect signals, functions, and accelerators
@@ -245,6 +288,22 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	loadConfCurrentWindowAction := glib.SimpleActionNew(constants.ActionLoadConfigCurrentWindow, nil)
 	loadConfNewWindowAction := glib.SimpleActionNew(constants.ActionLoadConfigNewWindow, nil)
 	getStatsWindowAction := glib.SimpleActionNew(constants.ActionGetStats, nil)
+	copyTXAction := glib.SimpleActionNew(constants.ActionCopyTX, nil)
+	copyTXAsTSVAction := glib.SimpleActionNew(constants.ActionCopyTXAsTSV, nil)
+	pasteTXAction := glib.SimpleActionNew(constants.ActionPasteTX, nil)
+	editRRuleAction := glib.SimpleActionNew(constants.ActionEditRRule, nil)
+	editSeasonalAction := glib.SimpleActionNew(constants.ActionEditSeasonal, nil)
+	editVariablesAction := glib.SimpleActionNew(constants.ActionEditVariables, nil)
+	editSweepsAction := glib.SimpleActionNew(constants.ActionEditSweeps, nil)
+	editInterestAction := glib.SimpleActionNew(constants.ActionEditInterest, nil)
+	newLoanAction := glib.SimpleActionNew(constants.ActionNewLoan, nil)
+	editLoanAction := glib.SimpleActionNew(constants.ActionEditLoan, nil)
+	debtPlannerAction := glib.SimpleActionNew(constants.ActionDebtPlanner, nil)
+	editGoalsAction := glib.SimpleActionNew(constants.ActionEditGoals, nil)
+	sinkingFundsAction := glib.SimpleActionNew(constants.ActionSinkingFunds, nil)
+	payPeriodsAction := glib.SimpleActionNew(constants.ActionPayPeriods, nil)
+	loadHolidaysAction := glib.SimpleActionNew(constants.ActionLoadHolidays, nil)
+	defaultHolidaysAction := glib.SimpleActionNew(constants.ActionDefaultHolidays, nil)
 	showAboutDialogAction := glib.SimpleActionNew(constants.ActionAbout, nil)
 
 	// create and insert custom action group with prefix "fin" (for finances)
@@ -256,6 +315,22 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	finActionGroup.AddAction(loadConfCurrentWindowAction)
 	finActionGroup.AddAction(loadConfNewWindowAction)
 	finActionGroup.AddAction(getStatsWindowAction)
+	finActionGroup.AddAction(copyTXAction)
+	finActionGroup.AddAction(copyTXAsTSVAction)
+	finActionGroup.AddAction(pasteTXAction)
+	finActionGroup.AddAction(editRRuleAction)
+	finActionGroup.AddAction(editSeasonalAction)
+	finActionGroup.AddAction(editVariablesAction)
+	finActionGroup.AddAction(editSweepsAction)
+	finActionGroup.AddAction(editInterestAction)
+	finActionGroup.AddAction(newLoanAction)
+	finActionGroup.AddAction(editLoanAction)
+	finActionGroup.AddAction(debtPlannerAction)
+	finActionGroup.AddAction(editGoalsAction)
+	finActionGroup.AddAction(sinkingFundsAction)
+	finActionGroup.AddAction(payPeriodsAction)
+	finActionGroup.AddAction(loadHolidaysAction)
+	finActionGroup.AddAction(defaultHolidaysAction)
 	finActionGroup.AddAction(showAboutDialogAction)
 
 	ws.Win.InsertActionGroup("fin", finActionGroup)
@@ -275,6 +350,22 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	loadConfCurrentWindowAction.Connect(constants.GtkSignalActivate, loadConfCurrentWindowFn)
 	loadConfNewWindowAction.Connect(constants.GtkSignalActivate, loadConfNewWindowFn)
 	getStatsWindowAction.Connect(constants.GtkSignalActivate, getStats)
+	copyTXAction.Connect(constants.GtkSignalActivate, copyConfItemsHandler)
+	copyTXAsTSVAction.Connect(constants.GtkSignalActivate, copyConfItemsAsTSVHandler)
+	pasteTXAction.Connect(constants.GtkSignalActivate, pasteConfItemsHandler)
+	editRRuleAction.Connect(constants.GtkSignalActivate, editRRuleHandler)
+	editSeasonalAction.Connect(constants.GtkSignalActivate, editSeasonalHandler)
+	editVariablesAction.Connect(constants.GtkSignalActivate, editVariablesHandler)
+	editSweepsAction.Connect(constants.GtkSignalActivate, editSweepsHandler)
+	editInterestAction.Connect(constants.GtkSignalActivate, editInterestHandler)
+	newLoanAction.Connect(constants.GtkSignalActivate, newLoanHandler)
+	editLoanAction.Connect(constants.GtkSignalActivate, editLoanHandler)
+	debtPlannerAction.Connect(constants.GtkSignalActivate, debtPlannerHandler)
+	editGoalsAction.Connect(constants.GtkSignalActivate, editGoalsHandler)
+	sinkingFundsAction.Connect(constants.GtkSignalActivate, sinkingFundsHandler)
+	payPeriodsAction.Connect(constants.GtkSignalActivate, payPeriodsHandler)
+	loadHolidaysAction.Connect(constants.GtkSignalActivate, loadHolidaysHandler)
+	defaultHolidaysAction.Connect(constants.GtkSignalActivate, defaultHolidaysHandler)
 	showAboutDialogAction.Connect(constants.GtkSignalActivate, showAboutDialog)
 
 	// buttons
@@ -294,6 +385,7 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	keyW, _ := gtk.AcceleratorParse("w")
 	keyC, _ := gtk.AcceleratorParse("c")
 	keyN, _ := gtk.AcceleratorParse("n")
+	keyE, _ := gtk.AcceleratorParse("e")
 	key1, modAlt := gtk.AcceleratorParse("<alt>1")
 	key2, _ := gtk.AcceleratorParse("2")
 	accelerators.Connect(keyQ, modCtrl, gtk.ACCEL_VISIBLE, quitApp)
@@ -314,8 +406,11 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	accelerators.Connect(keyB, modCtrl, gtk.ACCEL_VISIBLE, addConfItemHandler)
 	accelerators.Connect(keyD, modCtrlShift, gtk.ACCEL_VISIBLE, cloneConfItemHandler)
 	accelerators.Connect(keyI, modCtrl, gtk.ACCEL_VISIBLE, getStats)
+	accelerators.Connect(keyE, modCtrl, gtk.ACCEL_VISIBLE, toggleDetailPanel)
 	accelerators.Connect(key1, modAlt, gtk.ACCEL_VISIBLE, setTabToConfig)
 	accelerators.Connect(key2, modAlt, gtk.ACCEL_VISIBLE, setTabToResults)
+	accelerators.Connect(gdk.KEY_Up, modAlt, gtk.ACCEL_VISIBLE, moveConfItemsUp)
+	accelerators.Connect(gdk.KEY_Down, modAlt, gtk.ACCEL_VISIBLE, moveConfItemsDown)
 	accelerators.Connect(gdk.KEY_KP_Page_Down, modCtrlShift, gtk.ACCEL_VISIBLE, prevTab)
 	accelerators.Connect(gdk.KEY_KP_Page_Up, modCtrlShift, gtk.ACCEL_VISIBLE, nextTab)
 	accelerators.Connect(gdk.KEY_Tab, modCtrl, gtk.ACCEL_VISIBLE, nextTab)
@@ -325,11 +420,14 @@ func primary(application *gtk.Application, filename string) *state.WinState {
 	// to attach components to a grid and render them
 
 	cfgGrid.Attach(hideInactiveCheckbox, 0, constants.ScrolledWindowGridHeight, constants.HalfGridWidth, constants.ControlsGridHeight)
+	cfgGrid.Attach(manualOrderBtn, 1, constants.ScrolledWindowGridHeight, constants.HalfGridWidth, constants.ControlsGridHeight)
 	cfgGrid.Attach(addConfItemBtn, 0, constants.ScrolledWindowGridHeight+1, constants.HalfGridWidth, constants.ControlsGridHeight)
 	cfgGrid.Attach(delConfItemBtn, 1, constants.ScrolledWindowGridHeight+1, constants.HalfGridWidth, constants.ControlsGridHeight)
-	cfgGrid.Attach(cloneConfItemBtn, 0, constants.ScrolledWindowGridHeight+2, constants.FullGridWidth, constants.ControlsGridHeight)
+	cfgGrid.Attach(cloneConfItemBtn, 0, constants.ScrolledWindowGridHeight+2, constants.HalfGridWidth, constants.ControlsGridHeight)
+	cfgGrid.Attach(detailPanelBtn, 1, constants.ScrolledWindowGridHeight+2, constants.HalfGridWidth, constants.ControlsGridHeight)
 
-	resultsGrid.Attach(startingBalanceInput, 0, constants.ScrolledWindowGridHeight, constants.FullGridWidth, constants.ControlsGridHeight)
+	resultsGrid.Attach(startingBalanceInput, 0, constants.ScrolledWindowGridHeight, constants.HalfGridWidth, constants.ControlsGridHeight)
+	resultsGrid.Attach(minimumBalanceInput, 1, constants.ScrolledWindowGridHeight, constants.HalfGridWidth, constants.ControlsGridHeight)
 	resultsGrid.Attach(stDateInput, 0, constants.ScrolledWindowGridHeight+1, constants.HalfGridWidth, constants.ControlsGridHeight)
 	resultsGrid.Attach(endDateInput, 1, constants.ScrolledWindowGridHeight+1, constants.HalfGridWidth, constants.ControlsGridHeight)
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
 
 	lib "github.com/charles-m-knox/finance-planner-lib"
 
@@ -21,7 +22,7 @@ import (
 // FPConf is a configuration that is compatible with my other financial planning
 // applications.
 type Profile struct {
-	TX []lib.TX `yaml:"transactions"`
+	TX []planner.TX `yaml:"transactions"`
 	// Name            string   `yaml:"name"`
 	// Modified        bool     `yaml:"-"`
 	// SelectedRow     int      `yaml:"selectedRow"`
@@ -102,50 +103,59 @@ func GetTXIDByListStorePath(ls *gtk.ListStore, path *gtk.TreePath) (string, erro
 
 // LoadConfig can load from the same config files that finance-planner-tui
 // uses, but it cannot save to that same file currently.
-func LoadConfig(file string) (txs []lib.TX, err error) {
+//
+// JSON config files are either a plan object (see planner.Conf), or, for
+// files written by older versions of this application, a bare array of
+// transactions.
+func LoadConfig(file string) (conf planner.Conf, err error) {
 	if strings.HasSuffix(file, ".yml") || strings.HasSuffix(file, ".yaml") {
 		b, err := os.ReadFile(file)
 		if err != nil {
-			return txs, fmt.Errorf("failed to read config json: %v", err.Error())
+			return conf, fmt.Errorf("failed to read config json: %v", err.Error())
 		}
 
 		fpc := FPConf{}
 
 		err = yaml.Unmarshal(b, &fpc)
 		if err != nil {
-			return txs, fmt.Errorf("failed to unmarshal config yaml: %v", err.Error())
+			return conf, fmt.Errorf("failed to unmarshal config yaml: %v", err.Error())
 		}
 
 		if len(fpc.Profiles) == 0 {
-			return txs, errors.New("config file %v has no profiles")
+			return conf, errors.New("config file %v has no profiles")
 		}
 
-		return fpc.Profiles[0].TX, nil
+		conf.Transactions = fpc.Profiles[0].TX
+		planner.NormalizeOrder(conf.Transactions)
+		planner.NormalizeFrequencies(conf.Transactions)
+
+		return conf, nil
 	}
 
 	txJSON, err := os.ReadFile(file)
 	if err != nil {
-		return txs, fmt.Errorf("failed to read config json: %v", err.Error())
+		return conf, fmt.Errorf("failed to read config json: %v", err.Error())
 	}
 
-	err = json.Unmarshal(txJSON, &txs)
+	if strings.HasPrefix(strings.TrimSpace(string(txJSON)), "[") {
+		err = json.Unmarshal(txJSON, &conf.Transactions)
+	} else {
+		err = json.Unmarshal(txJSON, &conf)
+	}
 	if err != nil {
-		return txs, fmt.Errorf("failed to unmarshal config json: %v", err.Error())
+		return conf, fmt.Errorf("failed to unmarshal config json: %v", err.Error())
 	}
 
 	// apply an automatic order to each of the transactions, starting from 1,
 	// since the 0-value is default when undefined
-	// for i := range txs {
-	// 	if txs[i].Order == 0 {
-	// 		txs[i].Order = i + 1
-	// 	}
-	// }
+	planner.NormalizeOrder(conf.Transactions)
+	planner.NormalizeFrequencies(conf.Transactions)
 
 	return
 }
 
-func SaveConfig(file string, txs []lib.TX) error {
-	txJSON, err := json.Marshal(txs)
+func SaveConfig(file string, conf planner.Conf) error {
+	txJSON, err := json.Marshal(conf)
 	if err != nil {
 		return fmt.Errorf("failed to parse tx json: %v", err.Error())
 	}
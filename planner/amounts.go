@@ -0,0 +1,420 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// AmountChange is a new amount for a transaction that takes effect on a date,
+// such as a rent increase when a lease is renewed.
+type AmountChange struct {
+	// From is the day (YYYY-MM-DD) that the amount takes effect on.
+	From string `yaml:"from"`
+	// Amount replaces the transaction's amount from then on.
+	Amount int `yaml:"amount"`
+}
+
+// GetFrom returns the day that the change takes effect on, or a zero time if
+// it's not a valid date.
+func (c *AmountChange) GetFrom() time.Time {
+	return parseExceptionDate(c.From)
+}
+
+// String formats the change the way it's shown in the config view, such as
+// "$-1900.00 from 2027-06-01".
+func (c AmountChange) String() string {
+	return fmt.Sprintf("%v %v %v", FormatExceptionAmount(c.Amount), constants.AmountScheduleFrom, c.From)
+}
+
+// Escalation grows a transaction's amount by a percentage or a fixed amount
+// every few months, such as a raise every January.
+type Escalation struct {
+	// Percent is how much the amount grows by at each step, e.g. 4 for 4%.
+	Percent float64 `yaml:"percent,omitempty"`
+	// Amount is how much (in cents) the amount grows by at each step, if
+	// Percent isn't set. Both grow the size of the amount, so an expense
+	// becomes more expensive and income becomes larger; negative values
+	// shrink it instead.
+	Amount int `yaml:"amount,omitempty"`
+	// Months is the number of months between each step.
+	Months int `yaml:"months"`
+	// From is the day (YYYY-MM-DD) of the first step.
+	From string `yaml:"from"`
+}
+
+// GetFrom returns the day of the escalation's first step, or a zero time if
+// it's not a valid date.
+func (e *Escalation) GetFrom() time.Time {
+	return parseExceptionDate(e.From)
+}
+
+// String formats the escalation the way it's shown in the config view, such
+// as "+4% every 12 months from 2027-06-01".
+func (e Escalation) String() string {
+	step := ""
+
+	if e.Percent != 0 {
+		step = strconv.FormatFloat(e.Percent, 'f', -1, 64) + "%"
+		if e.Percent > 0 {
+			step = "+" + step
+		}
+	} else {
+		step = FormatExceptionAmount(e.Amount)
+	}
+
+	if e.Months == 1 {
+		return fmt.Sprintf(constants.AmountScheduleMonthlyFormat, step, e.From)
+	}
+
+	return fmt.Sprintf(constants.AmountScheduleEscalationFormat, step, e.Months, e.From)
+}
+
+// GetSteps returns how many times the escalation has been applied by day,
+// which is 0 before its first step.
+func (e *Escalation) GetSteps(day time.Time) int {
+	from := e.GetFrom()
+	if from.IsZero() || e.Months <= 0 || day.Before(from) {
+		return 0
+	}
+
+	months := (day.Year()-from.Year())*12 + int(day.Month()) - int(from.Month())
+	if day.Day() < from.Day() {
+		months--
+	}
+
+	return 1 + months/e.Months
+}
+
+// apply returns amount after the escalation's steps up to day.
+func (e *Escalation) apply(amount int, day time.Time) int {
+	steps := e.GetSteps(day)
+	if steps == 0 {
+		return amount
+	}
+
+	size := math.Abs(float64(amount))
+	sign := 1.0
+	if amount < 0 {
+		sign = -1
+	}
+
+	if e.Percent != 0 {
+		size *= math.Pow(1+e.Percent/100, float64(steps))
+	} else {
+		size += float64(e.Amount * steps)
+	}
+
+	return int(math.Round(sign * math.Max(size, 0)))
+}
+
+// GetAmount returns the transaction's amount as of day, which is its amount
+// for the month (see GetSeasonalAmount) changed by its most recent amount
+// change (if any) on or before day, and then grown by its escalation (if
+// any). An amount change replaces the seasonal amount table from its date
+// onwards. If the amount is linked to another transaction, it's the linked
+// percentage of that transaction's amount as of day instead.
+func (tx *TX) GetAmount(day time.Time) int {
+	if linked, ok := tx.getLinkedAmount(day); ok {
+		return linked
+	}
+
+	amount := tx.GetSeasonalAmount(day)
+	latest := time.Time{}
+
+	for i := range tx.AmountChanges {
+		from := tx.AmountChanges[i].GetFrom()
+		if !from.IsZero() && !from.After(day) && !from.Before(latest) {
+			amount = tx.AmountChanges[i].Amount
+			latest = from
+		}
+	}
+
+	if tx.Escalation != nil {
+		amount = tx.Escalation.apply(amount, day)
+	}
+
+	return amount
+}
+
+// GetAmountScheduleText formats the transaction's amount changes or its
+// escalation for a single line of text, such as a config view cell.
+func GetAmountScheduleText(tx *TX) string {
+	entries := []string{}
+	for _, c := range tx.AmountChanges {
+		entries = append(entries, c.String())
+	}
+
+	if tx.Escalation != nil {
+		entries = append(entries, tx.Escalation.String())
+	}
+
+	return strings.Join(entries, constants.ExceptionSeparator+" ")
+}
+
+// parseEscalation parses an escalation rule such as "+4% every 12 months from
+// 2027-06-01", "+$50 every year from 2027-01-01" or "3% every month from
+// 2027-01-01". The step is always positive unless it starts with a "-".
+func parseEscalation(fields []string) (*Escalation, error) {
+	e := &Escalation{}
+	step := fields[0]
+
+	if strings.HasSuffix(step, "%") {
+		p, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSuffix(step, "%"), "+"), 64)
+		if err != nil || p == 0 {
+			return nil, fmt.Errorf("\"%v\" is not a valid percentage; %v", step, constants.MsgAmountScheduleHint)
+		}
+
+		e.Percent = p
+	} else {
+		if !strings.ContainsAny(step, "123456789") {
+			return nil, fmt.Errorf("\"%v\" is not a valid amount; %v", step, constants.MsgAmountScheduleHint)
+		}
+
+		e.Amount = int(lib.ParseDollarAmount(step, true))
+	}
+
+	i := 2 // skips "every"
+	e.Months = 1
+
+	if i < len(fields) {
+		if n, err := strconv.Atoi(fields[i]); err == nil {
+			if n <= 0 {
+				return nil, fmt.Errorf("an escalation has to happen at least every month; %v", constants.MsgAmountScheduleHint)
+			}
+
+			e.Months = n
+			i++
+		}
+	}
+
+	if i >= len(fields) {
+		return nil, fmt.Errorf("the escalation is missing how often it happens; %v", constants.MsgAmountScheduleHint)
+	}
+
+	switch fields[i] {
+	case "month", "months":
+	case "year", "years":
+		e.Months *= 12
+	default:
+		return nil, fmt.Errorf("\"%v\" is not understood in the escalation, use months or years; %v", fields[i], constants.MsgAmountScheduleHint)
+	}
+
+	i++
+
+	if i+1 >= len(fields) || fields[i] != constants.AmountScheduleFrom {
+		return nil, fmt.Errorf("the escalation is missing the date of its first step; %v", constants.MsgAmountScheduleHint)
+	}
+
+	from, err := parseExceptionDateField(fields[i+1])
+	if err != nil {
+		return nil, fmt.Errorf("%v; %v", err.Error(), constants.MsgAmountScheduleHint)
+	}
+
+	if i+2 < len(fields) {
+		return nil, fmt.Errorf("\"%v\" is not understood in the escalation; %v", strings.Join(fields[i+2:], " "), constants.MsgAmountScheduleHint)
+	}
+
+	e.From = from
+
+	return e, nil
+}
+
+// parseAmountChange parses a single amount change such as "$-1900.00 from
+// 2027-06-01". The date can also come first, and "from" can be left out. It
+// can also end on a date, such as "$-1800.00 until 2027-05-31", which is
+// returned as until; in that case, the change's From may be empty, since it
+// can come from the previous entry (see ParseAmountSchedule). As with the
+// Amount column, the amount is an expense unless it starts with a "+".
+func parseAmountChange(fields []string) (c AmountChange, until string, err error) {
+	amount := ""
+	isUntil := false
+
+	for _, field := range fields {
+		switch {
+		case field == constants.AmountScheduleFrom || field == "on" || field == "starting":
+		case field == constants.AmountScheduleUntil:
+			isUntil = true
+		case strings.Count(field, "-") >= 2:
+			date, err := parseExceptionDateField(field)
+			if err != nil {
+				return c, "", fmt.Errorf("%v; %v", err.Error(), constants.MsgAmountScheduleHint)
+			}
+
+			if isUntil {
+				until = date
+				isUntil = false
+				continue
+			}
+
+			c.From = date
+		case strings.ContainsAny(field, "0123456789") && amount == "":
+			amount = field
+		default:
+			return c, "", fmt.Errorf("\"%v\" is not understood in the amount change \"%v\"; %v", field, strings.Join(fields, " "), constants.MsgAmountScheduleHint)
+		}
+	}
+
+	if amount == "" || isUntil {
+		return c, "", fmt.Errorf("the amount change \"%v\" needs an amount and the date it starts or ends on; %v", strings.Join(fields, " "), constants.MsgAmountScheduleHint)
+	}
+
+	c.Amount = ParseExceptionAmount(strings.ReplaceAll(amount, ",", ""))
+
+	return c, until, nil
+}
+
+// splitAmountSchedule splits an amount schedule into its entries, which are
+// separated by semicolons or by "then", and returns the lowercase fields of
+// each entry. Commas at the end of a field (such as "2027-05-31,") are
+// dropped, so that "$1,800 until 2027-05-31, then $1,900" is two entries.
+func splitAmountSchedule(s string) [][]string {
+	entries := [][]string{}
+
+	for _, entry := range strings.Split(s, constants.ExceptionSeparator) {
+		fields := []string{}
+
+		for _, field := range strings.Fields(strings.ToLower(entry)) {
+			field = strings.TrimRight(field, ",")
+			if field == "" {
+				continue
+			}
+
+			if field == constants.AmountScheduleThen {
+				entries = append(entries, fields)
+				fields = []string{}
+				continue
+			}
+
+			fields = append(fields, field)
+		}
+
+		entries = append(entries, fields)
+	}
+
+	return entries
+}
+
+// ParseAmountSchedule parses how a transaction's amount changes over time, as
+// entered by the user. It's either a list of amount changes separated by
+// semicolons, such as "$-1900.00 from 2027-06-01; $-2000.00 from 2028-06-01",
+// or a single escalation rule, such as "+4% every 12 months from 2027-06-01".
+// The amount changes are returned sorted by date. An empty value means that
+// the amount doesn't change.
+//
+// Amount changes can also be listed as the amounts that are in effect until a
+// date, such as "$1,800 until 2027-05-31, then $1,900", where each amount
+// after "then" starts on the day after the previous "until". If the first
+// amount has no start date, it's returned as initial, since it's the amount
+// before any of the changes; otherwise initial is nil. See SetAmountSchedule.
+func ParseAmountSchedule(s string) (initial *int, changes []AmountChange, escalation *Escalation, err error) {
+	changes = []AmountChange{}
+
+	// the previous entry's "until" date, and the day after it, which is when
+	// an entry without a start date of its own starts
+	until, next := "", ""
+
+	for _, fields := range splitAmountSchedule(s) {
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) > 1 && fields[1] == constants.AmountScheduleEvery {
+			if escalation != nil {
+				return nil, nil, nil, fmt.Errorf("only one escalation rule can be used; %v", constants.MsgAmountScheduleHint)
+			}
+
+			e, err := parseEscalation(fields)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			escalation = e
+			next = ""
+
+			continue
+		}
+
+		var c AmountChange
+
+		c, until, err = parseAmountChange(fields)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if c.From == "" {
+			c.From = next
+		}
+
+		next = ""
+		if until != "" {
+			d := parseExceptionDate(until).AddDate(0, 0, 1)
+			next = lib.GetDateString(d.Year(), int(d.Month()), d.Day())
+		}
+
+		if c.From == "" {
+			// only the first amount can be in effect from the beginning
+			if until == "" || initial != nil || len(changes) > 0 {
+				return nil, nil, nil, fmt.Errorf("the amount change \"%v\" needs the date it starts on; %v", strings.Join(fields, " "), constants.MsgAmountScheduleHint)
+			}
+
+			amount := c.Amount
+			initial = &amount
+
+			continue
+		}
+
+		// a later change for the same day replaces an earlier one
+		result := []AmountChange{}
+		for _, existing := range changes {
+			if existing.From != c.From {
+				result = append(result, existing)
+			}
+		}
+
+		changes = append(result, c)
+	}
+
+	if next != "" {
+		return nil, nil, nil, fmt.Errorf("the amount after %v is missing, e.g. \"then $1,900\"; %v", until, constants.MsgAmountScheduleHint)
+	}
+
+	if escalation != nil && len(changes) > 0 {
+		return nil, nil, nil, fmt.Errorf("use either amount changes or an escalation rule, not both; %v", constants.MsgAmountScheduleHint)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].From < changes[j].From })
+
+	return initial, changes, escalation, nil
+}
+
+// SetAmountSchedule sets the transaction's amount changes or escalation rule
+// from the Amount changes column as entered by the user; see
+// ParseAmountSchedule. If the schedule starts with the amount that's in
+// effect until a date, that amount replaces the transaction's amount, just
+// like typing it into the Amount column does.
+func (tx *TX) SetAmountSchedule(s string) error {
+	initial, changes, escalation, err := ParseAmountSchedule(s)
+	if err != nil {
+		return err
+	}
+
+	if initial != nil {
+		tx.Amount = *initial
+		tx.Formula = ""
+		tx.SeasonalAmounts = nil
+		tx.Link = nil
+	}
+
+	tx.AmountChanges = changes
+	tx.Escalation = escalation
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package planner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetAmountScheduleResolvesPerDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		amounts  map[time.Time]int
+	}{
+		{
+			name:     "until, then",
+			schedule: "$1,800 until 2027-05-31, then $1,900",
+			amounts: map[time.Time]int{
+				date(2026, 1, 1):  -180000,
+				date(2027, 5, 31): -180000,
+				date(2027, 6, 1):  -190000,
+				date(2030, 1, 1):  -190000,
+			},
+		},
+		{
+			name:     "several untils",
+			schedule: "+$5,000 until 2026-12-31, then +$5,200 until 2027-12-31, then +$5,400",
+			amounts: map[time.Time]int{
+				date(2026, 12, 31): 500000,
+				date(2027, 1, 1):   520000,
+				date(2028, 1, 1):   540000,
+			},
+		},
+		{
+			name:     "amount changes from dates",
+			schedule: "$-2000.00 from 2028-06-01; $-1900.00 from 2027-06-01",
+			amounts: map[time.Time]int{
+				date(2027, 5, 31): -100,
+				date(2027, 6, 1):  -190000,
+				date(2028, 6, 1):  -200000,
+			},
+		},
+		{
+			name:     "percentage escalation",
+			schedule: "+4% every 12 months from 2027-06-01",
+			amounts: map[time.Time]int{
+				date(2027, 5, 31): -100,
+				date(2027, 6, 1):  -104,
+				date(2028, 5, 31): -104,
+				date(2028, 6, 1):  -108,
+			},
+		},
+		{
+			name:     "fixed escalation every year",
+			schedule: "+$0.50 every year from 2027-01-01",
+			amounts: map[time.Time]int{
+				date(2026, 12, 31): -100,
+				date(2027, 1, 1):   -150,
+				date(2029, 1, 1):   -250,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := TX{}
+			tx.Amount = -100
+
+			if err := tx.SetAmountSchedule(tt.schedule); err != nil {
+				t.Fatalf("SetAmountSchedule returned an error: %v", err)
+			}
+
+			for day, want := range tt.amounts {
+				if got := tx.GetAmount(day); got != want {
+					t.Errorf("the amount on %v is %v, want %v", day.Format(time.DateOnly), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAmountScheduleErrors(t *testing.T) {
+	for _, s := range []string{
+		"$1,800 until 2027-05-31",
+		"$1,900 from 2027-01-01; $1,800 until 2027-05-31, then $1,900",
+		"$1,800 until",
+		"$1,800",
+		"soon $1,800",
+		"+4% every 12 months",
+		"+4% every 12 fortnights from 2027-01-01",
+		"+4% every 12 months from 2027-01-01; +5% every 12 months from 2028-01-01",
+		"+4% every 12 months from 2027-01-01; $1,900 from 2027-06-01",
+	} {
+		if _, _, _, err := ParseAmountSchedule(s); err == nil {
+			t.Errorf("ParseAmountSchedule(%q) didn't return an error", s)
+		}
+	}
+}
+
+func TestGetAmountScheduleTextRoundTrips(t *testing.T) {
+	for _, s := range []string{
+		"$-1900.00 from 2027-06-01; $-2000.00 from 2028-06-01",
+		"+4% every 12 months from 2027-06-01",
+		"+$50.00 every month from 2027-01-01",
+	} {
+		tx := TX{}
+		if err := tx.SetAmountSchedule(s); err != nil {
+			t.Fatalf("SetAmountSchedule(%q) returned an error: %v", s, err)
+		}
+
+		if got := GetAmountScheduleText(&tx); got != s {
+			t.Errorf("got %q, want %q", got, s)
+		}
+	}
+}
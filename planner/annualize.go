@@ -0,0 +1,151 @@
+package planner
+
+import (
+	"math"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// daysPerYear is the average length of a year, accounting for leap years.
+const daysPerYear = 365.25
+
+// GetYearlyOccurrences calculates how many times per year a transaction occurs
+// on average, based on its frequency, interval and weekdays. Its start and end
+// dates are ignored, since the point is to show what a transaction costs while
+// it's ongoing. Transactions with a recurrence rule are instead counted over
+// the year following now.
+//
+// Weekly transactions are calculated the same way as the results, which
+// treat the interval as a number of days and only count the checked weekdays (e.g. a Monday-only weekly transaction with an interval of 2 occurs
+// every other Monday).
+func GetYearlyOccurrences(tx *TX, now time.Time) float64 {
+	if tx.RRule != "" {
+		// the start and end dates are ignored here too
+		t := *tx
+		t.StartsYear, t.StartsMonth, t.StartsDay = 0, 0, 0
+		t.EndsYear, t.EndsMonth, t.EndsDay = 0, 0, 0
+
+		day := toDay(now)
+		occurrences, err := t.getRRuleOccurrences(day, day, day.AddDate(1, 0, -1))
+		if err != nil {
+			return 0
+		}
+
+		return float64(len(occurrences))
+	}
+
+	interval := float64(tx.Interval)
+	if interval <= 0 {
+		interval = 1
+	}
+
+	switch tx.Frequency {
+	case constants.YEARLY:
+		return 1 / interval
+	case constants.MONTHLY:
+		return 12 / interval
+	case constants.DAILY:
+		return daysPerYear / interval
+	}
+
+	weekdays := 0
+	for i := range constants.Weekdays {
+		if tx.Weekdays[i] {
+			weekdays++
+		}
+	}
+
+	// like the results, no weekdays at all is the same as every weekday
+	if weekdays == 0 {
+		return daysPerYear / interval
+	}
+
+	if tx.Interval%7 != 0 {
+		// stepping by a number of days that isn't a multiple of 7 eventually
+		// lands on every weekday equally often
+		return float64(weekdays) * daysPerYear / 7 / interval
+	}
+
+	// stepping by whole weeks always lands on the start date's weekday, so
+	// only that weekday can occur
+	if starts := tx.GetStartsDate(); !starts.IsZero() {
+		// time.Weekday starts from Sunday, whereas tx.Weekdays starts from
+		// Monday
+		if !tx.Weekdays[(int(starts.Weekday())+6)%7] {
+			return 0
+		}
+	}
+
+	return daysPerYear / interval
+}
+
+// GetYearlyAmount calculates the total amount (in cents) that a transaction
+// adds up to over an average year, at the amount that's in effect as of now.
+// A transaction with a seasonal amount table uses the average of its amounts
+// over the next twelve months instead. See GetYearlyOccurrences.
+func GetYearlyAmount(tx *TX, now time.Time) int {
+	return int(math.Round(getAnnualizedAmount(tx, now) * GetYearlyOccurrences(tx, now)))
+}
+
+// GetMonthlyAmount calculates the total amount (in cents) that a transaction
+// adds up to over an average month; see GetYearlyAmount.
+func GetMonthlyAmount(tx *TX, now time.Time) int {
+	return int(math.Round(getAnnualizedAmount(tx, now) * GetYearlyOccurrences(tx, now) / 12))
+}
+
+// getAnnualizedAmount is the amount that GetYearlyAmount multiplies by.
+func getAnnualizedAmount(tx *TX, now time.Time) float64 {
+	if tx.HasSeasonalAmounts() {
+		return tx.getAverageAmount(now)
+	}
+
+	return float64(tx.GetAmount(toDay(now)))
+}
+
+// RecurringTotals are the combined yearly amounts of a set of transactions,
+// split into income and expenses. Expenses are negative.
+type RecurringTotals struct {
+	YearlyIncome   int
+	YearlyExpenses int
+}
+
+// YearlyNet is the sum of the yearly income and expenses.
+func (t RecurringTotals) YearlyNet() int {
+	return t.YearlyIncome + t.YearlyExpenses
+}
+
+// MonthlyIncome is the average monthly income.
+func (t RecurringTotals) MonthlyIncome() int {
+	return int(math.Round(float64(t.YearlyIncome) / 12))
+}
+
+// MonthlyExpenses is the average monthly expenses.
+func (t RecurringTotals) MonthlyExpenses() int {
+	return int(math.Round(float64(t.YearlyExpenses) / 12))
+}
+
+// MonthlyNet is the sum of the average monthly income and expenses.
+func (t RecurringTotals) MonthlyNet() int {
+	return int(math.Round(float64(t.YearlyNet()) / 12))
+}
+
+// GetRecurringTotals adds up the yearly amounts of every active transaction.
+func GetRecurringTotals(txs []TX, now time.Time) RecurringTotals {
+	totals := RecurringTotals{}
+
+	for i := range txs {
+		if !txs[i].Active {
+			continue
+		}
+
+		yearly := GetYearlyAmount(&txs[i], now)
+		if yearly > 0 {
+			totals.YearlyIncome += yearly
+		} else {
+			totals.YearlyExpenses += yearly
+		}
+	}
+
+	return totals
+}
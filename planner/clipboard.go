@@ -0,0 +1,431 @@
+package planner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+	uuid "github.com/charles-m-knox/go-uuid"
+)
+
+// GetTXAsJSON serializes transactions for the clipboard, in the same format
+// as they're stored in a config file.
+func GetTXAsJSON(txs []TX) (string, error) {
+	b, err := json.MarshalIndent(txs, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tx json: %v", err.Error())
+	}
+
+	return string(b), nil
+}
+
+// FormatAmount formats an amount (in cents) as a plain signed decimal, such as
+// -5.00 for a $5.00 expense. This is friendlier to spreadsheets than
+// lib.FormatAsCurrency, and it can be parsed back with
+// lib.ParseDollarAmount(s, true).
+func FormatAmount(a int) string {
+	return strings.Replace(lib.FormatAsCurrency(a), "$", "", 1)
+}
+
+// GetTXField returns the value of a single config column for a transaction as
+// a plain string, suitable for exporting to e.g. a spreadsheet.
+func GetTXField(tx *TX, column string) string {
+	switch column {
+	case constants.ColumnOrder:
+		return strconv.Itoa(tx.Order)
+	case constants.ColumnAmount:
+		return FormatAmount(tx.Amount)
+	case constants.ColumnAmounts:
+		return GetAmountScheduleText(tx)
+	case constants.ColumnSeasonal:
+		return GetSeasonalAmountsText(tx)
+	case constants.ColumnLink:
+		return GetLinkText(tx)
+	case constants.ColumnActive:
+		return strconv.FormatBool(tx.Active)
+	case constants.ColumnName:
+		return tx.Name
+	case constants.ColumnFrequency:
+		return tx.Frequency
+	case constants.ColumnInterval:
+		return strconv.Itoa(tx.Interval)
+	case constants.ColumnMonthlyRule:
+		return tx.MonthlyRule
+	case constants.ColumnStarts:
+		return tx.GetStartDateString()
+	case constants.ColumnEnds:
+		return tx.GetEndsDateString()
+	case constants.ColumnRRule:
+		return tx.RRule
+	case constants.ColumnShift:
+		return tx.Shift
+	case constants.ColumnExceptions:
+		return GetExceptionsText(tx.Exceptions)
+	case constants.ColumnNote:
+		return tx.Note
+	case constants.ColumnID:
+		return tx.ID
+	case constants.ColumnCreatedAt:
+		return tx.CreatedAt.Format(time.RFC3339)
+	case constants.ColumnUpdatedAt:
+		return tx.UpdatedAt.Format(time.RFC3339)
+	}
+
+	for i, weekday := range constants.Weekdays {
+		if weekday == column {
+			return strconv.FormatBool(tx.Weekdays[i])
+		}
+	}
+
+	return ""
+}
+
+// GetTXAsTSV serializes transactions as tab-separated values with a header
+// row, which can be pasted directly into a spreadsheet.
+func GetTXAsTSV(txs []TX) string {
+	clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	columns := []string{}
+	for _, column := range constants.ConfigColumns {
+		if !constants.ComputedConfigColumns[column] {
+			columns = append(columns, column)
+		}
+	}
+
+	b := new(strings.Builder)
+	b.WriteString(strings.Join(columns, "\t"))
+	b.WriteString("\n")
+
+	for i := range txs {
+		fields := make([]string, len(columns))
+		for j, column := range columns {
+			fields[j] = clean.Replace(GetTXField(&txs[i], column))
+		}
+
+		b.WriteString(strings.Join(fields, "\t"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// ParseBool interprets the many ways a spreadsheet might represent a checkbox.
+func ParseBool(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "y", "x", "1", "on", "checked":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetTXField sets a single config column of a transaction from a plain string,
+// as the inverse of GetTXField. Amounts are signed, so "-5.00" is an expense.
+// Columns that can't be set this way (such as the ID) are ignored.
+func SetTXField(tx *TX, column string, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch column {
+	case constants.ColumnAmount:
+		// a formula's amount is calculated once the plan's variables are
+		// known; see ApplyFormulas
+		tx.Formula = ""
+		if IsFormula(value) {
+			tx.Formula = NormalizeFormula(value)
+			break
+		}
+
+		tx.Amount = int(lib.ParseDollarAmount(value, true))
+	case constants.ColumnAmounts:
+		return tx.SetAmountSchedule(value)
+	case constants.ColumnSeasonal:
+		amounts, err := ParseSeasonalAmounts(value)
+		if err != nil {
+			return err
+		}
+
+		tx.SeasonalAmounts = amounts
+	case constants.ColumnLink:
+		link, err := ParseAmountLink(value)
+		if err != nil {
+			return err
+		}
+
+		tx.Link = link
+	case constants.ColumnActive:
+		tx.Active = ParseBool(value)
+	case constants.ColumnName:
+		tx.Name = value
+	case constants.ColumnFrequency:
+		f, err := ParseFrequency(value)
+		if err != nil {
+			return err
+		}
+
+		tx.Frequency = f
+	case constants.ColumnInterval:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid interval %v: %v", value, err.Error())
+		}
+
+		if n <= 0 {
+			n = 1
+		}
+
+		tx.Interval = n
+	case constants.ColumnMonthlyRule:
+		r, err := ParseMonthlyRule(value)
+		if err != nil {
+			return err
+		}
+
+		tx.MonthlyRule = ""
+		if r != nil {
+			tx.MonthlyRule = r.String()
+		}
+	case constants.ColumnStarts:
+		tx.StartsYear, tx.StartsMonth, tx.StartsDay = lib.ParseYearMonthDateString(value)
+	case constants.ColumnEnds:
+		tx.EndsYear, tx.EndsMonth, tx.EndsDay = lib.ParseYearMonthDateString(value)
+	case constants.ColumnRRule:
+		if value != "" {
+			if _, err := ParseRRule(value, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		tx.RRule = NormalizeRRule(value)
+	case constants.ColumnShift:
+		shift, err := ParseShift(value)
+		if err != nil {
+			return err
+		}
+
+		tx.Shift = shift
+	case constants.ColumnExceptions:
+		exceptions, err := ParseExceptions(value)
+		if err != nil {
+			return err
+		}
+
+		tx.Exceptions = exceptions
+	case constants.ColumnNote:
+		tx.Note = value
+	default:
+		for i, weekday := range constants.Weekdays {
+			if strings.EqualFold(weekday, column) {
+				tx.Weekdays[i] = ParseBool(value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParseFrequency normalizes user input for the Frequency column, accepting
+// shorthands such as "m" for MONTHLY. The input is case-insensitive.
+func ParseFrequency(s string) (string, error) {
+	f := strings.ToUpper(strings.TrimSpace(s))
+
+	switch f {
+	case constants.Y:
+		f = constants.YEARLY
+	case constants.W:
+		f = constants.WEEKLY
+	case constants.M:
+		f = constants.MONTHLY
+	case constants.D:
+		f = constants.DAILY
+	}
+
+	if GetFrequencyRank(f) < 0 {
+		return "", errors.New(constants.MsgInvalidRecurrence)
+	}
+
+	return f, nil
+}
+
+// GetFrequencyRank returns the position of a frequency within
+// constants.Frequencies (so that DAILY sorts before WEEKLY, and so on), or -1
+// if it's not a recognized frequency.
+func GetFrequencyRank(f string) int {
+	for i, frequency := range constants.Frequencies {
+		if f == frequency {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NormalizeFrequencies rewrites the frequency of each transaction into the
+// form used throughout this application (e.g. "daily" becomes DAILY), so that
+// config files written by hand or by other applications behave the same way.
+// Frequencies that aren't recognized are left alone so that validation can
+// point them out.
+//
+// Daily transactions occur every Interval days, so their weekdays are
+// cleared; the lib would otherwise only let them occur on the checked
+// weekdays.
+func NormalizeFrequencies(txs []TX) {
+	for i := range txs {
+		f, err := ParseFrequency(txs[i].Frequency)
+		if err != nil {
+			continue
+		}
+
+		txs[i].Frequency = f
+
+		if f == constants.DAILY {
+			txs[i].Weekdays = lib.GetWeekdaysMap()
+		}
+	}
+}
+
+// getColumnByHeader finds the config column that a spreadsheet header refers
+// to, ignoring case and surrounding whitespace.
+func getColumnByHeader(header string) string {
+	header = strings.TrimSpace(header)
+
+	for _, column := range constants.ConfigColumns {
+		if strings.EqualFold(column, header) {
+			return column
+		}
+	}
+
+	// a couple of friendlier aliases
+	switch strings.ToLower(header) {
+	case "notes":
+		return constants.ColumnNote
+	case "start", "starts on":
+		return constants.ColumnStarts
+	case "end", "ends on":
+		return constants.ColumnEnds
+	}
+
+	return ""
+}
+
+func parseTSV(text string, now time.Time) ([]TX, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 2 {
+		return nil, errors.New("expected a header row followed by at least one transaction")
+	}
+
+	headers := strings.Split(lines[0], "\t")
+	columns := make([]string, len(headers))
+	recognized := 0
+
+	for i, header := range headers {
+		columns[i] = getColumnByHeader(header)
+		if columns[i] != "" {
+			recognized++
+		}
+	}
+
+	if recognized == 0 {
+		return nil, errors.New("none of the header row's columns were recognized")
+	}
+
+	txs := []TX{}
+
+	for n, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		tx := TX{TX: lib.GetNewTX(now)}
+		fields := strings.Split(line, "\t")
+
+		for i, field := range fields {
+			if i >= len(columns) || columns[i] == "" {
+				continue
+			}
+
+			err := SetTXField(&tx, columns[i], field)
+			if err != nil {
+				return nil, fmt.Errorf("row %v: %v", n+1, err.Error())
+			}
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+func parseJSON(text string) ([]TX, error) {
+	txs := []TX{}
+
+	if strings.HasPrefix(text, "{") {
+		tx := TX{}
+
+		err := json.Unmarshal([]byte(text), &tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tx json: %v", err.Error())
+		}
+
+		return append(txs, tx), nil
+	}
+
+	err := json.Unmarshal([]byte(text), &txs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tx json: %v", err.Error())
+	}
+
+	return txs, nil
+}
+
+// ParseClipboardTX parses transactions that were pasted from the clipboard,
+// either as JSON (as produced by GetTXAsJSON) or as tab-separated values with
+// a header row, whose columns are mapped by name. Every pasted transaction
+// gets a fresh ID and timestamps, and is placed at the end of the manual order
+// of existing.
+func ParseClipboardTX(text string, now time.Time, existing []TX) ([]TX, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("the clipboard is empty")
+	}
+
+	var txs []TX
+	var err error
+
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		txs, err = parseJSON(text)
+	} else {
+		txs, err = parseTSV(text, now)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	order := GetNextOrder(existing)
+
+	for i := range txs {
+		txs[i].ID = uuid.New()
+		txs[i].CreatedAt = now
+		txs[i].UpdatedAt = now
+		txs[i].Selected = false
+		txs[i].Order = order + i
+
+		if txs[i].Weekdays == nil {
+			txs[i].Weekdays = lib.GetWeekdaysMap()
+		}
+
+		if txs[i].Interval <= 0 {
+			txs[i].Interval = 1
+		}
+	}
+
+	NormalizeFrequencies(txs)
+
+	return txs, nil
+}
@@ -0,0 +1,36 @@
+package planner
+
+// Conf is everything that gets saved to (and loaded from) a config file for a
+// single financial plan.
+type Conf struct {
+	// Transactions are the recurring transaction definitions of the plan.
+	Transactions []TX `yaml:"transactions"`
+
+	// ConfigSort is the sort that was applied to the config view, in order of
+	// priority. When empty, the manual order is used.
+	ConfigSort []SortKey `yaml:"configSort"`
+
+	// HolidaysFile is the holiday calendar (see LoadHolidays) that
+	// transactions are moved off of. When empty, the US federal holidays are
+	// used.
+	HolidaysFile string `yaml:"holidaysFile,omitempty"`
+
+	// Variables are named numbers that amounts can refer to in formulas,
+	// such as "salary = 5200".
+	Variables []Variable `yaml:"variables,omitempty"`
+
+	// Sweeps are the rules that move money to or from other accounts when
+	// the balance crosses a threshold (see SweepRule).
+	Sweeps []SweepRule `yaml:"sweeps,omitempty"`
+
+	// Interest is how interest accrues on the balance, if it does.
+	Interest *Interest `yaml:"interest,omitempty"`
+
+	// Goals are the savings goals that the projected balance is checked
+	// against; see Goal.
+	Goals []Goal `yaml:"goals,omitempty"`
+
+	// MinimumBalance is the balance (in cents) that the safe-to-spend amount
+	// keeps the projected balance above; see GetSafeToSpend.
+	MinimumBalance int `yaml:"minimumBalance,omitempty"`
+}
@@ -0,0 +1,336 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// maxDebtPlanMonths is how long a debt payoff plan is simulated for before
+// giving up, since a budget that barely covers the interest may never pay the
+// debts off.
+const maxDebtPlanMonths = 1200
+
+// Debt is a debt that's paid down by a debt payoff plan, such as a credit
+// card balance. The amounts are in cents.
+type Debt struct {
+	Name    string
+	Balance int
+	// Rate is the annual percentage rate (APR), e.g. 24.99 for 24.99%.
+	Rate    float64
+	Minimum int
+}
+
+// String formats the debt the way it's entered in the debt payoff planner,
+// such as "Visa; $4500.00; 24.99%; $90.00".
+func (d Debt) String() string {
+	sep := constants.ExceptionSeparator + " "
+
+	return strings.Join([]string{
+		d.Name,
+		lib.FormatAsCurrency(d.Balance),
+		strconv.FormatFloat(d.Rate, 'f', -1, 64) + "%",
+		lib.FormatAsCurrency(d.Minimum),
+	}, sep)
+}
+
+// DebtPayoff is how a debt is paid off by a debt payoff plan.
+type DebtPayoff struct {
+	Debt Debt
+	// Payments are the monthly payments (in cents), starting with the plan's
+	// first month and ending with the month that the debt is paid off in.
+	Payments []int
+	// Interest is the interest that's charged until the debt is paid off.
+	Interest int
+}
+
+// DebtPlan is the result of paying down debts with a monthly budget using a
+// strategy (constants.DebtSnowball or constants.DebtAvalanche).
+type DebtPlan struct {
+	Strategy string
+	Start    time.Time
+	// Payoffs are in the order that the strategy pays the debts off in.
+	Payoffs []DebtPayoff
+}
+
+// GetPayoffDate returns the day of the debt's last payment.
+func (p *DebtPlan) GetPayoffDate(i int) time.Time {
+	return p.Start.AddDate(0, len(p.Payoffs[i].Payments)-1, 0)
+}
+
+// GetTotalInterest returns the interest that's charged on all of the debts.
+func (p *DebtPlan) GetTotalInterest() int {
+	total := 0
+	for _, payoff := range p.Payoffs {
+		total += payoff.Interest
+	}
+
+	return total
+}
+
+// GetDebtFreeDate returns the day of the plan's last payment.
+func (p *DebtPlan) GetDebtFreeDate() time.Time {
+	last := p.Start
+	for i := range p.Payoffs {
+		if d := p.GetPayoffDate(i); d.After(last) {
+			last = d
+		}
+	}
+
+	return last
+}
+
+// GetSummary summarizes the plan, such as "Avalanche: debt-free on Mon
+// 2028-05-01, with $1,234.56 of interest".
+func (p *DebtPlan) GetSummary() string {
+	return fmt.Sprintf(
+		constants.DebtPlanSummaryFormat,
+		GetDebtStrategyName(p.Strategy),
+		p.GetDebtFreeDate().Format(constants.DetailDateFormat),
+		lib.FormatAsCurrency(p.GetTotalInterest()),
+	)
+}
+
+// GetDebtStrategyName returns the display name of a debt payoff strategy.
+func GetDebtStrategyName(strategy string) string {
+	if strategy == constants.DebtAvalanche {
+		return constants.DebtAvalancheName
+	}
+
+	return constants.DebtSnowballName
+}
+
+// ParseDebts parses debts as entered by the user, with one per line, each of
+// which is its name, balance, APR and minimum payment separated by
+// semicolons, such as "Visa; $4,500; 24.99%; $90". Blank lines and lines
+// starting with "#" are ignored.
+func ParseDebts(s string) ([]Debt, error) {
+	debts := []Debt{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+
+	parseAmount := func(n int, s string) (int, error) {
+		s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+		if !strings.ContainsAny(s, "0123456789") {
+			return 0, fmt.Errorf("line %v: \"%v\" is not an amount; %v", n, s, constants.MsgDebtsHint)
+		}
+
+		return int(math.Abs(float64(lib.ParseDollarAmount(s, true)))), nil
+	}
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, constants.ExceptionSeparator)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %v: \"%v\" is not a debt; %v", n, line, constants.MsgDebtsHint)
+		}
+
+		d := Debt{Name: strings.TrimSpace(fields[0])}
+		if d.Name == "" {
+			return nil, fmt.Errorf("line %v: the debt needs a name; %v", n, constants.MsgDebtsHint)
+		}
+
+		var err error
+		d.Balance, err = parseAmount(n, fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		d.Rate, err = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[2]), "%"), 64)
+		if err != nil || d.Rate < 0 || d.Rate > 100 {
+			return nil, fmt.Errorf("line %v: \"%v\" is not an APR between 0%% and 100%%; %v", n, strings.TrimSpace(fields[2]), constants.MsgDebtsHint)
+		}
+
+		d.Minimum, err = parseAmount(n, fields[3])
+		if err != nil {
+			return nil, err
+		}
+
+		debts = append(debts, d)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read debts: %v", err.Error())
+	}
+
+	return debts, nil
+}
+
+// GetDebtsText formats the debts with one per line.
+func GetDebtsText(debts []Debt) string {
+	lines := []string{}
+	for _, d := range debts {
+		lines = append(lines, d.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sortDebts returns the debts in the order that the strategy pays them off
+// in: the snowball pays the smallest balance first, and the avalanche pays
+// the highest APR first.
+func sortDebts(debts []Debt, strategy string) []Debt {
+	sorted := append([]Debt{}, debts...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if strategy == constants.DebtAvalanche && a.Rate != b.Rate {
+			return a.Rate > b.Rate
+		}
+
+		if a.Balance != b.Balance {
+			return a.Balance < b.Balance
+		}
+
+		return a.Rate > b.Rate
+	})
+
+	return sorted
+}
+
+// GetDebtPlan simulates paying down the debts with a fixed monthly budget,
+// starting on start. Every month, interest is charged on each balance, the
+// minimum payment of every debt is paid, and the rest of the budget goes to
+// the debts in the strategy's order. When a debt is paid off, its minimum
+// payment rolls over to the next one, since the budget stays the same.
+func GetDebtPlan(debts []Debt, budget int, start time.Time, strategy string) (DebtPlan, error) {
+	plan := DebtPlan{Strategy: strategy, Start: start}
+
+	if len(debts) == 0 {
+		return plan, fmt.Errorf("enter at least one debt")
+	}
+
+	minimums := 0
+	for _, d := range debts {
+		minimums += d.Minimum
+	}
+
+	if budget < minimums {
+		return plan, fmt.Errorf("the monthly budget of %v doesn't cover the minimum payments, which add up to %v", lib.FormatAsCurrency(budget), lib.FormatAsCurrency(minimums))
+	}
+
+	sorted := sortDebts(debts, strategy)
+	balances := []int{}
+	for _, d := range sorted {
+		plan.Payoffs = append(plan.Payoffs, DebtPayoff{Debt: d})
+		balances = append(balances, d.Balance)
+	}
+
+	for month := 0; ; month++ {
+		remaining := 0
+		for _, b := range balances {
+			remaining += b
+		}
+
+		if remaining == 0 {
+			break
+		}
+
+		if month == maxDebtPlanMonths {
+			return plan, fmt.Errorf("the debts aren't paid off within %v years, since the monthly budget of %v barely covers the interest", maxDebtPlanMonths/monthsPerYear, lib.FormatAsCurrency(budget))
+		}
+
+		payments := make([]int, len(balances))
+		left := budget
+
+		// interest and minimum payments
+		for i := range balances {
+			if balances[i] == 0 {
+				continue
+			}
+
+			interest := int(math.Round(float64(balances[i]) * plan.Payoffs[i].Debt.Rate / 100 / monthsPerYear))
+			plan.Payoffs[i].Interest += interest
+			balances[i] += interest
+
+			payments[i] = min(plan.Payoffs[i].Debt.Minimum, balances[i])
+			left -= payments[i]
+		}
+
+		// the rest of the budget, in the strategy's order
+		for i := range balances {
+			extra := min(left, balances[i]-payments[i])
+			payments[i] += extra
+			left -= extra
+		}
+
+		for i := range balances {
+			if balances[i] == 0 {
+				continue
+			}
+
+			balances[i] -= payments[i]
+			plan.Payoffs[i].Payments = append(plan.Payoffs[i].Payments, payments[i])
+		}
+	}
+
+	return plan, nil
+}
+
+// GetDebtPlanTXs returns the monthly payment of each debt in the plan as a
+// transaction that starts on the plan's first month and ends when the debt
+// is paid off. The payment steps up with an amount change whenever another
+// debt's payment rolls over to it, and one-off payments (such as the last
+// one) are exceptions. The transactions are placed after txs in the manual
+// ordering.
+func GetDebtPlanTXs(plan DebtPlan, now time.Time, txs []TX) []TX {
+	result := []TX{}
+	all := append([]TX{}, txs...)
+
+	for i, payoff := range plan.Payoffs {
+		if len(payoff.Payments) == 0 {
+			continue
+		}
+
+		tx := GetNewTX(now, all)
+		end := plan.GetPayoffDate(i)
+
+		tx.Name = fmt.Sprintf(constants.DebtPaymentNameFormat, payoff.Debt.Name)
+		tx.Note = fmt.Sprintf(constants.DebtPaymentNoteFormat, GetDebtStrategyName(plan.Strategy), lib.FormatAsCurrency(payoff.Interest))
+		tx.Frequency = constants.MONTHLY
+		tx.Interval = 1
+		tx.StartsYear, tx.StartsMonth, tx.StartsDay = plan.Start.Year(), int(plan.Start.Month()), plan.Start.Day()
+		tx.EndsYear, tx.EndsMonth, tx.EndsDay = end.Year(), int(end.Month()), end.Day()
+
+		payments := payoff.Payments
+		current := payments[0]
+		tx.Amount = -current
+
+		for m := 1; m < len(payments); m++ {
+			p := payments[m]
+			if p == current {
+				continue
+			}
+
+			date := lib.GetNowDateString(plan.Start.AddDate(0, m, 0))
+
+			// a payment that continues the next month is a step up (or
+			// down), and anything else only applies to this month
+			if m+1 < len(payments) && payments[m+1] == p {
+				current = p
+				tx.AmountChanges = append(tx.AmountChanges, AmountChange{From: date, Amount: -p})
+
+				continue
+			}
+
+			amount := -p
+			tx.Exceptions = tx.SetException(Exception{Date: date, Amount: &amount})
+		}
+
+		result = append(result, tx)
+		all = append(all, tx)
+	}
+
+	return result
+}
@@ -0,0 +1,148 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+var testDebts = []Debt{
+	{Name: "Visa", Balance: 450000, Rate: 24.99, Minimum: 9000},
+	{Name: "Car", Balance: 1200000, Rate: 6.5, Minimum: 30000},
+	{Name: "Store card", Balance: 80000, Rate: 29.99, Minimum: 2500},
+}
+
+func TestGetDebtPlan(t *testing.T) {
+	tests := []struct {
+		strategy string
+		order    []string
+	}{
+		{constants.DebtSnowball, []string{"Store card", "Visa", "Car"}},
+		{constants.DebtAvalanche, []string{"Store card", "Visa", "Car"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			budget := 60000
+			plan, err := GetDebtPlan(testDebts, budget, date(2026, 1, 1), tt.strategy)
+			if err != nil {
+				t.Fatalf("GetDebtPlan returned an error: %v", err)
+			}
+
+			balances, paid := 0, 0
+			months := 0
+
+			for i, payoff := range plan.Payoffs {
+				if payoff.Debt.Name != tt.order[i] {
+					t.Errorf("debt %v is %v, want %v", i, payoff.Debt.Name, tt.order[i])
+				}
+
+				balances += payoff.Debt.Balance
+				for _, p := range payoff.Payments {
+					paid += p
+				}
+
+				months = max(months, len(payoff.Payments))
+			}
+
+			// every payment goes towards a balance or its interest
+			if want := balances + plan.GetTotalInterest(); paid != want {
+				t.Errorf("the payments add up to %v, want the balances plus interest of %v", paid, want)
+			}
+
+			// no month goes over the budget, and every month but the last
+			// uses all of it
+			for m := 0; m < months; m++ {
+				total := 0
+				for _, payoff := range plan.Payoffs {
+					if m < len(payoff.Payments) {
+						total += payoff.Payments[m]
+					}
+				}
+
+				if total > budget || (m < months-1 && total != budget) {
+					t.Errorf("month %v pays %v of the budget of %v", m+1, total, budget)
+				}
+			}
+
+			if want := date(2026, 1, 1).AddDate(0, months-1, 0); !plan.GetDebtFreeDate().Equal(want) {
+				t.Errorf("debt-free on %v, want %v", plan.GetDebtFreeDate(), want)
+			}
+		})
+	}
+}
+
+func TestGetDebtPlanAvalanchePaysHighestRateFirst(t *testing.T) {
+	debts := []Debt{
+		{Name: "Low", Balance: 100000, Rate: 5, Minimum: 2000},
+		{Name: "High", Balance: 500000, Rate: 25, Minimum: 10000},
+	}
+
+	snowball, err := GetDebtPlan(debts, 50000, date(2026, 1, 1), constants.DebtSnowball)
+	if err != nil {
+		t.Fatalf("GetDebtPlan returned an error: %v", err)
+	}
+
+	avalanche, err := GetDebtPlan(debts, 50000, date(2026, 1, 1), constants.DebtAvalanche)
+	if err != nil {
+		t.Fatalf("GetDebtPlan returned an error: %v", err)
+	}
+
+	if snowball.Payoffs[0].Debt.Name != "Low" || avalanche.Payoffs[0].Debt.Name != "High" {
+		t.Errorf("the snowball pays %v first and the avalanche pays %v first", snowball.Payoffs[0].Debt.Name, avalanche.Payoffs[0].Debt.Name)
+	}
+
+	if avalanche.GetTotalInterest() > snowball.GetTotalInterest() {
+		t.Errorf("the avalanche charges %v of interest, more than the snowball's %v", avalanche.GetTotalInterest(), snowball.GetTotalInterest())
+	}
+}
+
+func TestGetDebtPlanErrors(t *testing.T) {
+	if _, err := GetDebtPlan(nil, 10000, date(2026, 1, 1), constants.DebtSnowball); err == nil {
+		t.Error("no error without any debts")
+	}
+
+	if _, err := GetDebtPlan(testDebts, 40000, date(2026, 1, 1), constants.DebtSnowball); err == nil {
+		t.Error("no error for a budget below the minimum payments")
+	}
+
+	interestOnly := []Debt{{Name: "Loan", Balance: 1000000, Rate: 12, Minimum: 10000}}
+	if _, err := GetDebtPlan(interestOnly, 10000, date(2026, 1, 1), constants.DebtSnowball); err == nil {
+		t.Error("no error for a budget that only covers the interest")
+	}
+}
+
+func TestGetDebtPlanTXs(t *testing.T) {
+	start := date(2026, 1, 1)
+
+	plan, err := GetDebtPlan(testDebts, 60000, start, constants.DebtSnowball)
+	if err != nil {
+		t.Fatalf("GetDebtPlan returned an error: %v", err)
+	}
+
+	txs := GetDebtPlanTXs(plan, time.Now(), nil)
+	if len(txs) != len(plan.Payoffs) {
+		t.Fatalf("got %v transactions, want %v", len(txs), len(plan.Payoffs))
+	}
+
+	// each transaction's occurrences are the plan's payments for its debt
+	for i := range txs {
+		payments := plan.Payoffs[i].Payments
+
+		occurrences, err := GetResultOccurrences(&txs[i], start, plan.GetDebtFreeDate(), Holidays{})
+		if err != nil {
+			t.Fatalf("GetResultOccurrences returned an error: %v", err)
+		}
+
+		if len(occurrences) != len(payments) {
+			t.Fatalf("%v occurs %v times, want %v", txs[i].Name, len(occurrences), len(payments))
+		}
+
+		for m, o := range occurrences {
+			if o.Amount != -payments[m] {
+				t.Errorf("%v pays %v on %v, want %v", txs[i].Name, o.Amount, o.Date, -payments[m])
+			}
+		}
+	}
+}
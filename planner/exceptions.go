@@ -0,0 +1,236 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// Exception changes a single occurrence of a transaction, such as a month
+// that a membership is paused for, or a bill that's unusually large once.
+type Exception struct {
+	// Date is the day (YYYY-MM-DD) that the occurrence is scheduled for,
+	// before it's moved off of a weekend or holiday.
+	Date string `yaml:"date"`
+	// Skip removes the occurrence from the results.
+	Skip bool `yaml:"skip,omitempty"`
+	// Amount replaces the transaction's amount for this occurrence, if set.
+	Amount *int `yaml:"amount,omitempty"`
+	// MoveTo is the day (YYYY-MM-DD) that the occurrence happens on instead,
+	// if set. It isn't moved off of weekends or holidays.
+	MoveTo string `yaml:"moveTo,omitempty"`
+}
+
+// GetDate returns the day that the exception applies to, or a zero time if
+// it's not a valid date.
+func (e *Exception) GetDate() time.Time {
+	return parseExceptionDate(e.Date)
+}
+
+// GetMoveTo returns the day that the exception moves its occurrence to, or a
+// zero time if it isn't moved (or it's not a valid date).
+func (e *Exception) GetMoveTo() time.Time {
+	return parseExceptionDate(e.MoveTo)
+}
+
+// IsEmpty returns true if the exception doesn't change anything.
+func (e *Exception) IsEmpty() bool {
+	return !e.Skip && e.Amount == nil && e.MoveTo == ""
+}
+
+func parseExceptionDate(s string) time.Time {
+	yr, mo, day, err := ParseDate(s)
+	if err != nil || !IsValidDate(yr, mo, day) {
+		return time.Time{}
+	}
+
+	return time.Date(yr, time.Month(mo), day, 0, 0, 0, 0, time.UTC)
+}
+
+// FormatExceptionAmount formats an amount so that ParseExceptionAmount reads
+// it back the same way, i.e. with a "+" in front of income.
+func FormatExceptionAmount(a int) string {
+	if a >= 0 {
+		return "+" + lib.FormatAsCurrency(a)
+	}
+
+	return lib.FormatAsCurrency(a)
+}
+
+// ParseExceptionAmount parses an amount the same way as the Amount column,
+// which means that it's an expense unless it starts with a "+".
+func ParseExceptionAmount(s string) int {
+	return int(lib.ParseDollarAmount(strings.TrimSpace(s), false))
+}
+
+// String formats the exception the way it's shown in the config view, such as
+// "2026-11-01 skip" or "2026-12-01 $-250.00 to 2026-12-03".
+func (e Exception) String() string {
+	parts := []string{e.Date}
+
+	if e.Skip {
+		parts = append(parts, constants.ExceptionSkip)
+	}
+
+	if e.Amount != nil {
+		parts = append(parts, FormatExceptionAmount(*e.Amount))
+	}
+
+	if e.MoveTo != "" {
+		parts = append(parts, constants.ExceptionMoveTo, e.MoveTo)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// GetExceptionsText formats a list of exceptions for a single line of text,
+// such as a config view cell.
+func GetExceptionsText(exceptions []Exception) string {
+	entries := []string{}
+	for _, e := range exceptions {
+		entries = append(entries, e.String())
+	}
+
+	return strings.Join(entries, constants.ExceptionSeparator+" ")
+}
+
+// parseExceptionDateField parses a date for an exception and returns it in
+// the form that it's saved in.
+func parseExceptionDateField(s string) (string, error) {
+	yr, mo, day, err := ParseDate(s)
+	if err != nil {
+		return "", err
+	}
+
+	if !IsValidDate(yr, mo, day) {
+		return "", fmt.Errorf("\"%v\" is not a valid date", s)
+	}
+
+	return lib.GetDateString(yr, mo, day), nil
+}
+
+// ParseException parses a single exception as entered by the user, such as
+// "2026-11-01 skip", "2026-12-01 $-250.00" (or "+$20" for income) or
+// "2026-12-01 to 2026-12-03". The amount and the new date can be combined.
+func ParseException(s string) (Exception, error) {
+	e := Exception{}
+	fields := strings.Fields(strings.ToLower(s))
+
+	if len(fields) == 0 {
+		return e, fmt.Errorf("an exception needs a date; %v", constants.MsgExceptionHint)
+	}
+
+	date, err := parseExceptionDateField(fields[0])
+	if err != nil {
+		return e, fmt.Errorf("%v; %v", err.Error(), constants.MsgExceptionHint)
+	}
+
+	e.Date = date
+
+	for i := 1; i < len(fields); i++ {
+		switch fields[i] {
+		case constants.ExceptionSkip, "skipped", "skips":
+			e.Skip = true
+		case constants.ExceptionMoveTo, "->", "moved", "move":
+			if fields[i] != constants.ExceptionMoveTo && i+1 < len(fields) && fields[i+1] == constants.ExceptionMoveTo {
+				i++
+			}
+
+			if i+1 >= len(fields) {
+				return e, fmt.Errorf("the exception on %v is missing the date to move it to; %v", date, constants.MsgExceptionHint)
+			}
+
+			i++
+
+			e.MoveTo, err = parseExceptionDateField(fields[i])
+			if err != nil {
+				return e, fmt.Errorf("%v; %v", err.Error(), constants.MsgExceptionHint)
+			}
+		default:
+			if strings.Count(fields[i], "-") >= 2 {
+				return e, fmt.Errorf("put \"%v\" before the date to move the exception on %v to; %v", constants.ExceptionMoveTo, date, constants.MsgExceptionHint)
+			}
+
+			if !strings.ContainsAny(fields[i], "0123456789") {
+				return e, fmt.Errorf("\"%v\" is not understood in the exception on %v; %v", fields[i], date, constants.MsgExceptionHint)
+			}
+
+			a := ParseExceptionAmount(fields[i])
+			e.Amount = &a
+		}
+	}
+
+	if e.IsEmpty() {
+		return e, fmt.Errorf("the exception on %v doesn't change anything; %v", date, constants.MsgExceptionHint)
+	}
+
+	return e, nil
+}
+
+// ParseExceptions parses a list of exceptions as entered by the user, such as
+// "2026-11-01 skip; 2026-12-01 $-250.00", and returns them sorted by date. An
+// empty value means that there are no exceptions.
+func ParseExceptions(s string) ([]Exception, error) {
+	exceptions := []Exception{}
+
+	for _, entry := range strings.Split(s, constants.ExceptionSeparator) {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		e, err := ParseException(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		exceptions = setException(exceptions, e)
+	}
+
+	return exceptions, nil
+}
+
+// setException adds e to exceptions, replacing any existing exception for the
+// same date, and keeps them sorted by date. An empty exception removes the
+// existing one.
+func setException(exceptions []Exception, e Exception) []Exception {
+	result := []Exception{}
+	for _, existing := range exceptions {
+		if existing.Date != e.Date {
+			result = append(result, existing)
+		}
+	}
+
+	if !e.IsEmpty() {
+		result = append(result, e)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+
+	return result
+}
+
+// SetException returns the transaction's exceptions with e added, replacing
+// any existing exception for the same date. If e doesn't change anything, the
+// existing exception for its date is removed instead.
+func (tx *TX) SetException(e Exception) []Exception {
+	return setException(tx.Exceptions, e)
+}
+
+// GetException returns the transaction's exception for the occurrence that's
+// scheduled on day, or nil if there isn't one.
+func (tx *TX) GetException(day time.Time) *Exception {
+	date := lib.GetNowDateString(day)
+
+	for i := range tx.Exceptions {
+		if tx.Exceptions[i].Date == date {
+			return &tx.Exceptions[i]
+		}
+	}
+
+	return nil
+}
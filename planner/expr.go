@@ -0,0 +1,194 @@
+package planner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// exprParser evaluates arithmetic expressions such as "salary*0.1" or
+// "(1200+300)/12", with +, -, *, /, parentheses and named variables, using the
+// usual precedence rules.
+type exprParser struct {
+	s    string
+	pos  int
+	vars map[string]float64
+}
+
+// EvalExpression evaluates an arithmetic expression, looking up names in vars
+// (case-insensitively; see GetVariableValues). Numbers may have a "$" in front
+// and commas between their digits, such as "$1,850.00".
+func EvalExpression(s string, vars map[string]float64) (float64, error) {
+	p := &exprParser{s: s, vars: vars}
+
+	v, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return 0, fmt.Errorf("unexpected \"%v\" in \"%v\"", p.s[p.pos:], s)
+	}
+
+	return v, nil
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space character, or 0 at the end.
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+func (p *exprParser) parseSum() (float64, error) {
+	v, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			r, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+
+			v += r
+		case '-':
+			p.pos++
+			r, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+
+			v -= r
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) parseProduct() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			r, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+
+			v *= r
+		case '/':
+			p.pos++
+			r, err := p.parseUnary()
+			if err != nil {
+				return 0, err
+			}
+
+			if r == 0 {
+				return 0, fmt.Errorf("\"%v\" divides by zero", p.s)
+			}
+
+			v /= r
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.parseUnary()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+
+	return p.parseValue()
+}
+
+func (p *exprParser) parseValue() (float64, error) {
+	c := p.peek()
+
+	switch {
+	case c == 0:
+		return 0, fmt.Errorf("\"%v\" ends too early", p.s)
+	case c == '(':
+		p.pos++
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("\"%v\" is missing a \")\"", p.s)
+		}
+
+		p.pos++
+
+		return v, nil
+	case c == '$' || c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.s) && strings.ContainsRune("$,.0123456789", rune(p.s[p.pos])) {
+			p.pos++
+		}
+
+		text := p.s[start:p.pos]
+		v, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "").Replace(text), 64)
+		if err != nil {
+			return 0, fmt.Errorf("\"%v\" is not a number", text)
+		}
+
+		return v, nil
+	case isVariableNameChar(rune(c)) && !isDigit(rune(c)):
+		start := p.pos
+		for p.pos < len(p.s) && isVariableNameChar(rune(p.s[p.pos])) {
+			p.pos++
+		}
+
+		name := p.s[start:p.pos]
+		v, ok := p.vars[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("there's no variable named \"%v\"", name)
+		}
+
+		return v, nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+
+	return 0, fmt.Errorf("unexpected \"%v\" in \"%v\"", string(r), p.s)
+}
+
+// isVariableNameChar returns true for the characters that variable names can
+// consist of, which are ASCII letters, digits and underscores.
+func isVariableNameChar(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || isDigit(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
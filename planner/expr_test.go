@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvalExpression(t *testing.T) {
+	vars := map[string]float64{"salary": 5200, "rent": 1850, "tax_rate": 0.25}
+
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"1+2*3", 7},
+		{"(1+2)*3", 9},
+		{"10-4-3", 3},
+		{"12/4/3", 1},
+		{"-3+5", 2},
+		{"+salary*0.1", 520},
+		{"--2", 2},
+		{"$1,850.00/2", 925},
+		{" SALARY * tax_rate ", 1300},
+		{"(salary-rent)/2", 1675},
+		{".5*4", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalExpression(tt.expr, vars)
+			if err != nil {
+				t.Fatalf("EvalExpression returned an error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"", "ends too early"},
+		{"1+", "ends too early"},
+		{"(1+2", "missing a \")\""},
+		{"1/0", "divides by zero"},
+		{"1/(salary-salary)", "divides by zero"},
+		{"bonus*2", "no variable named \"bonus\""},
+		{"2 3", "unexpected \"3\""},
+		{"1.2.3", "not a number"},
+		{"2^3", "unexpected \"^3\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := EvalExpression(tt.expr, map[string]float64{"salary": 5200})
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// Goal is a balance to reach by a date, such as a $12,000 emergency fund by
+// 2027-06-01.
+type Goal struct {
+	Name string `yaml:"name"`
+	// Target is the balance to reach, in cents.
+	Target int `yaml:"target"`
+	// Date is the day (YYYY-MM-DD) to reach it by.
+	Date string `yaml:"date"`
+}
+
+// GetDate returns the day that the goal is to be reached by, or a zero time if
+// it's not a valid date.
+func (g *Goal) GetDate() time.Time {
+	return parseExceptionDate(g.Date)
+}
+
+// String formats the goal the way it's entered in the savings goals editor,
+// such as "$12000.00 Emergency fund by 2027-06-01".
+func (g Goal) String() string {
+	return fmt.Sprintf("%v %v %v %v", lib.FormatAsCurrency(g.Target), g.Name, constants.GoalBy, g.Date)
+}
+
+// GoalStatus is how a goal is projected to turn out.
+type GoalStatus struct {
+	Goal Goal
+	// InRange is false if the goal's date is outside of the results, in which
+	// case the rest of the fields aren't set.
+	InRange bool
+	// Projected is the balance on the goal's date.
+	Projected int
+	// Difference is the surplus (if positive) or the shortfall (if negative)
+	// on the goal's date.
+	Difference int
+	// ExtraMonthly is how much more would have to be saved each month from
+	// the start of the results to reach the goal, which is 0 if it's on
+	// track.
+	ExtraMonthly int
+}
+
+// IsOnTrack returns true if the goal is projected to be reached.
+func (s *GoalStatus) IsOnTrack() bool {
+	return s.InRange && s.Difference >= 0
+}
+
+// GetStatusText describes the goal's status, such as "on track, $1,200.00 to
+// spare" or "$500.00 short; save $83.34 more a month".
+func (s *GoalStatus) GetStatusText() string {
+	switch {
+	case !s.InRange:
+		return constants.GoalOutOfRange
+	case s.IsOnTrack():
+		return fmt.Sprintf(constants.GoalOnTrackFormat, lib.FormatAsCurrency(s.Difference))
+	}
+
+	return fmt.Sprintf(constants.GoalShortFormat, lib.FormatAsCurrency(-s.Difference), lib.FormatAsCurrency(s.ExtraMonthly))
+}
+
+// ParseGoals parses savings goals as entered by the user, with one per line,
+// each of which is its target balance, its name and the date to reach it by,
+// such as "$12,000 Emergency fund by 2027-06-01". Blank lines and lines
+// starting with "#" are ignored.
+func ParseGoals(s string) ([]Goal, error) {
+	goals := []Goal{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.EqualFold(fields[len(fields)-2], constants.GoalBy) {
+			return nil, fmt.Errorf("line %v: \"%v\" is not a goal; %v", n, line, constants.MsgGoalsHint)
+		}
+
+		amount := strings.ReplaceAll(fields[0], ",", "")
+		if !strings.ContainsAny(amount, "0123456789") {
+			return nil, fmt.Errorf("line %v: \"%v\" is not an amount; %v", n, fields[0], constants.MsgGoalsHint)
+		}
+
+		date, err := parseExceptionDateField(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", n, err.Error())
+		}
+
+		goals = append(goals, Goal{
+			Name:   strings.Join(fields[1:len(fields)-2], " "),
+			Target: int(lib.ParseDollarAmount(amount, true)),
+			Date:   date,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read goals: %v", err.Error())
+	}
+
+	return goals, nil
+}
+
+// GetGoalsText formats the goals with one per line.
+func GetGoalsText(goals []Goal) string {
+	lines := []string{}
+	for _, g := range goals {
+		lines = append(lines, g.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GetGoalStatuses evaluates each goal against the projected balances in the
+// results. The extra monthly amount spreads the shortfall over the whole
+// months from the start of the results until the goal's date (at least one).
+func GetGoalStatuses(goals []Goal, results []lib.Result) []GoalStatus {
+	statuses := []GoalStatus{}
+
+	byDay := make(map[string]*lib.Result)
+	for i := range results {
+		byDay[lib.GetNowDateString(results[i].Date)] = &results[i]
+	}
+
+	for _, g := range goals {
+		s := GoalStatus{Goal: g}
+
+		r, ok := byDay[g.Date]
+		if ok {
+			s.InRange = true
+			s.Projected = r.Balance
+			s.Difference = r.Balance - g.Target
+
+			if s.Difference < 0 {
+				start, end := results[0].Date, g.GetDate()
+				months := (end.Year()-start.Year())*monthsPerYear + int(end.Month()) - int(start.Month())
+				if end.Day() < start.Day() {
+					months--
+				}
+
+				months = max(months, 1)
+				s.ExtraMonthly = int(math.Ceil(float64(-s.Difference) / float64(months)))
+			}
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses
+}
+
+// GetGoalMarkers returns the goals that are due on each day (YYYY-MM-DD), for
+// marking them on a balance view.
+func GetGoalMarkers(goals []Goal) map[string][]Goal {
+	markers := make(map[string][]Goal)
+	for _, g := range goals {
+		markers[g.Date] = append(markers[g.Date], g)
+	}
+
+	return markers
+}
@@ -0,0 +1,155 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Holidays are the days (besides weekends) that banks don't post transactions
+// on, keyed by their date at midnight UTC, with the holiday's name as the
+// value.
+type Holidays map[time.Time]string
+
+// usFederalHoliday is a holiday that falls on a fixed date (when nth is 0), or
+// on the nth weekday of a month (-1 for the last).
+type usFederalHoliday struct {
+	name    string
+	month   time.Month
+	day     int
+	weekday time.Weekday
+	nth     int
+	since   int // the first year it was observed, if it's recent
+}
+
+var usFederalHolidays = []usFederalHoliday{
+	{name: "New Year's Day", month: time.January, day: 1},
+	{name: "Martin Luther King Jr. Day", month: time.January, weekday: time.Monday, nth: 3},
+	{name: "Washington's Birthday", month: time.February, weekday: time.Monday, nth: 3},
+	{name: "Memorial Day", month: time.May, weekday: time.Monday, nth: -1},
+	{name: "Juneteenth", month: time.June, day: 19, since: 2022},
+	{name: "Independence Day", month: time.July, day: 4},
+	{name: "Labor Day", month: time.September, weekday: time.Monday, nth: 1},
+	{name: "Columbus Day", month: time.October, weekday: time.Monday, nth: 2},
+	{name: "Veterans Day", month: time.November, day: 11},
+	{name: "Thanksgiving Day", month: time.November, weekday: time.Thursday, nth: 4},
+	{name: "Christmas Day", month: time.December, day: 25},
+}
+
+// getNthWeekday returns the nth (or the last, if n is -1) weekday of a month.
+func getNthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// GetUSFederalHolidays returns the US federal holidays from the start of
+// fromYear to the end of toYear, as observed by the Federal Reserve, which is
+// what determines when US banks post transactions. Holidays that fall on a
+// Sunday are observed on the following Monday, but unlike for federal
+// employees, holidays that fall on a Saturday are not observed on the Friday
+// before.
+func GetUSFederalHolidays(fromYear, toYear int) Holidays {
+	h := Holidays{}
+
+	for year := fromYear; year <= toYear; year++ {
+		for _, holiday := range usFederalHolidays {
+			if year < holiday.since {
+				continue
+			}
+
+			if holiday.nth != 0 {
+				h[getNthWeekday(year, holiday.month, holiday.weekday, holiday.nth)] = holiday.name
+				continue
+			}
+
+			day := time.Date(year, holiday.month, holiday.day, 0, 0, 0, 0, time.UTC)
+			if day.Weekday() == time.Sunday {
+				day = day.AddDate(0, 0, 1)
+			}
+
+			h[day] = holiday.name
+		}
+	}
+
+	return h
+}
+
+// ParseHolidays reads a holiday calendar, which has one holiday per line in
+// the form "2026-12-24 Christmas Eve". The name is optional, and can also be
+// separated from the date by a comma or a tab. Blank lines and lines starting
+// with "#" are ignored.
+func ParseHolidays(s string) (Holidays, error) {
+	h := Holidays{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(strings.Replace(line, ",", " ", 1))
+		date, name := fields[0], strings.Join(fields[1:], " ")
+		yr, mo, day, err := ParseDate(date)
+		if err != nil || !IsValidDate(yr, mo, day) {
+			return nil, fmt.Errorf("line %v: \"%v\" is not a date in the format YYYY-MM-DD", n, date)
+		}
+
+		if name == "" {
+			name = date
+		}
+
+		h[time.Date(yr, time.Month(mo), day, 0, 0, 0, 0, time.UTC)] = name
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read holidays: %v", err.Error())
+	}
+
+	return h, nil
+}
+
+// LoadHolidays reads a holiday calendar file; see ParseHolidays.
+func LoadHolidays(file string) (Holidays, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holiday calendar: %v", err.Error())
+	}
+
+	h, err := ParseHolidays(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse holiday calendar %v: %v", file, err.Error())
+	}
+
+	return h, nil
+}
+
+// getHolidays returns h, or the US federal holidays around start and end if
+// h is nil (as opposed to empty), since that's the default calendar.
+func getHolidays(h Holidays, start, end time.Time) Holidays {
+	if h != nil {
+		return h
+	}
+
+	return GetUSFederalHolidays(start.Year()-1, end.Year()+1)
+}
+
+// IsBusinessDay returns true if day is neither a weekend nor a holiday.
+func (h Holidays) IsBusinessDay(day time.Time) bool {
+	switch day.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+
+	_, holiday := h[toDay(day)]
+
+	return !holiday
+}
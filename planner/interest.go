@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// Interest defines how interest accrues on the plan's balance, such as 4% APY
+// on savings while the balance is positive, and 24% APR on a carried
+// credit card balance while it's negative. Interest accrues every day and is
+// posted as its own line item on the posting day of each month.
+type Interest struct {
+	// SavingsRate is the annual percentage yield (APY) that's earned while the
+	// balance is positive, e.g. 4 for 4%.
+	SavingsRate float64 `yaml:"savingsRate,omitempty"`
+	// DebtRate is the annual percentage rate (APR) that's charged while the
+	// balance is negative, e.g. 24 for 24%.
+	DebtRate float64 `yaml:"debtRate,omitempty"`
+	// Compounding is constants.InterestDaily to compound every day (so that
+	// interest accrues on the interest that hasn't been posted yet), or
+	// constants.InterestMonthly to charge the monthly rate on the average
+	// daily balance, as many banks and cards do.
+	Compounding string `yaml:"compounding,omitempty"`
+	// PostingDay is the day of the month that the accrued interest is posted
+	// on, which is the month's last day if the month is shorter.
+	PostingDay int `yaml:"postingDay,omitempty"`
+}
+
+// IsEnabled returns true if any interest accrues.
+func (in *Interest) IsEnabled() bool {
+	return in != nil && (in.SavingsRate != 0 || in.DebtRate != 0)
+}
+
+// String summarizes the interest settings, such as "4% APY, 24% APR,
+// compounded daily, posted on day 1".
+func (in Interest) String() string {
+	return fmt.Sprintf(
+		constants.InterestSummaryFormat,
+		formatPercent(in.SavingsRate),
+		formatPercent(in.DebtRate),
+		in.getCompounding(),
+		in.getPostingDay(),
+	)
+}
+
+// formatPercent formats a percentage with as few decimals as needed, e.g.
+// "4.5%".
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%v%%", math.Round(p*1000)/1000)
+}
+
+func (in *Interest) getCompounding() string {
+	if in.Compounding == constants.InterestMonthly {
+		return constants.InterestMonthly
+	}
+
+	return constants.InterestDaily
+}
+
+func (in *Interest) getPostingDay() int {
+	if in.PostingDay < 1 || in.PostingDay > 31 {
+		return 1
+	}
+
+	return in.PostingDay
+}
+
+// isPostingDay returns true if the accrued interest is posted on day, which is
+// the posting day of its month, or the month's last day if the month doesn't
+// have the posting day.
+func (in *Interest) isPostingDay(day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	postingDay := in.getPostingDay()
+	if postingDay > last {
+		postingDay = last
+	}
+
+	return day.Day() == postingDay
+}
+
+// getDailyRate returns the rate that accrues each day on balance. The APY is
+// converted to the equivalent periodic rate so that a year of compounding
+// earns exactly the APY, whereas the APR is simply divided up, as card
+// issuers do.
+func (in *Interest) getDailyRate(balance float64) float64 {
+	if balance < 0 {
+		return in.DebtRate / 100 / daysPerYear
+	}
+
+	if in.getCompounding() == constants.InterestMonthly {
+		// the monthly rate, spread over the days of the month so that it's
+		// charged on the average daily balance
+		return (math.Pow(1+in.SavingsRate/100, 1.0/monthsPerYear) - 1) * monthsPerYear / daysPerYear
+	}
+
+	return math.Pow(1+in.SavingsRate/100, 1/daysPerYear) - 1
+}
+
+// accrue adds a day's interest on balance to accrued, which is the interest
+// that hasn't been posted yet, and returns the new total.
+func (in *Interest) accrue(balance int, accrued float64) float64 {
+	base := float64(balance)
+	if in.getCompounding() == constants.InterestDaily {
+		base += accrued
+	}
+
+	return accrued + base*in.getDailyRate(base)
+}
+
+// getInterestName returns how posted interest of amount is listed in the day's
+// transaction names, such as "Interest earned ($12.34)".
+func getInterestName(amount int) string {
+	format := constants.InterestEarnedNameFormat
+	if amount < 0 {
+		format = constants.InterestChargedNameFormat
+	}
+
+	return fmt.Sprintf(format, lib.FormatAsCurrency(amount))
+}
+
+// applyInterest accrues a day's interest on the balance at the end of a
+// result's day, and posts everything that has accrued if it's the posting day,
+// by adding it to the day's income or expenses and its transaction names. It
+// returns the balance and the interest that's accrued but not yet posted.
+func applyInterest(in *Interest, r *lib.Result, balance int, accrued float64) (int, float64) {
+	if !in.IsEnabled() {
+		return balance, 0
+	}
+
+	accrued = in.accrue(balance, accrued)
+	if !in.isPostingDay(r.Date) {
+		return balance, accrued
+	}
+
+	amount := int(math.Round(accrued))
+	if amount == 0 {
+		return balance, accrued
+	}
+
+	if amount > 0 {
+		r.DayIncome += amount
+	} else {
+		r.DayExpenses += amount
+	}
+
+	r.DayNet += amount
+	r.DayTransactionNamesSlice = append(r.DayTransactionNamesSlice, getInterestName(amount))
+
+	return balance + amount, 0
+}
+
+// ValidateInterest checks interest settings as entered by the user.
+func ValidateInterest(in Interest) error {
+	if in.SavingsRate < 0 || in.SavingsRate > 100 {
+		return fmt.Errorf("the savings rate must be between 0%% and 100%%, not %v", formatPercent(in.SavingsRate))
+	}
+
+	if in.DebtRate < 0 || in.DebtRate > 100 {
+		return fmt.Errorf("the debt rate must be between 0%% and 100%%, not %v", formatPercent(in.DebtRate))
+	}
+
+	if in.PostingDay < 1 || in.PostingDay > 31 {
+		return fmt.Errorf("the posting day must be a day of the month from 1 to 31, not %v", in.PostingDay)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// postInterest applies interest to balance on every day from start to end
+// (inclusive), and returns the balance and the interest that was posted.
+func postInterest(in *Interest, balance int, start, end time.Time) (int, int) {
+	accrued := 0.0
+	posted := 0
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		r := lib.Result{Date: day}
+		balance, accrued = applyInterest(in, &r, balance, accrued)
+		posted += r.DayNet
+	}
+
+	return balance, posted
+}
+
+func TestApplyInterest(t *testing.T) {
+	tests := []struct {
+		name       string
+		interest   Interest
+		balance    int
+		start, end time.Time
+		want       int
+	}{
+		{
+			name:     "a year of 4% APY compounded daily",
+			interest: Interest{SavingsRate: 4, Compounding: constants.InterestDaily, PostingDay: 1},
+			balance:  1000000,
+			start:    date(2026, 1, 2),
+			end:      date(2027, 1, 1),
+			want:     39972,
+		},
+		{
+			name:     "a month of 24% APR on a negative balance",
+			interest: Interest{DebtRate: 24, Compounding: constants.InterestDaily, PostingDay: 1},
+			balance:  -100000,
+			start:    date(2026, 1, 2),
+			end:      date(2026, 2, 1),
+			want:     -2057,
+		},
+		{
+			name:     "a month of 4% APY on the average daily balance",
+			interest: Interest{SavingsRate: 4, Compounding: constants.InterestMonthly, PostingDay: 1},
+			balance:  1000000,
+			start:    date(2026, 1, 2),
+			end:      date(2026, 2, 1),
+			want:     3334,
+		},
+		{
+			name:     "not posted before the posting day",
+			interest: Interest{SavingsRate: 4, PostingDay: 15},
+			balance:  1000000,
+			start:    date(2026, 1, 1),
+			end:      date(2026, 1, 14),
+			want:     0,
+		},
+		{
+			name:     "disabled",
+			interest: Interest{},
+			balance:  1000000,
+			start:    date(2026, 1, 1),
+			end:      date(2026, 12, 31),
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, posted := postInterest(&tt.interest, tt.balance, tt.start, tt.end)
+
+			// each month's posting is rounded to the cent, which can add up
+			// to a few cents over a year
+			if diff := posted - tt.want; diff < -2 || diff > 2 {
+				t.Errorf("posted %v of interest, want %v", posted, tt.want)
+			}
+
+			if balance != tt.balance+posted {
+				t.Errorf("the balance is %v, want %v", balance, tt.balance+posted)
+			}
+		})
+	}
+}
+
+func TestIsPostingDay(t *testing.T) {
+	in := Interest{PostingDay: 31}
+
+	tests := []struct {
+		day  time.Time
+		want bool
+	}{
+		{date(2026, 1, 31), true},
+		{date(2026, 1, 30), false},
+		{date(2026, 2, 28), true},
+		{date(2028, 2, 28), false},
+		{date(2028, 2, 29), true},
+		{date(2026, 4, 30), true},
+	}
+
+	for _, tt := range tests {
+		if got := in.isPostingDay(tt.day); got != tt.want {
+			t.Errorf("isPostingDay(%v) = %v, want %v", tt.day.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
+
+func TestValidateInterest(t *testing.T) {
+	tests := []struct {
+		interest Interest
+		ok       bool
+	}{
+		{Interest{SavingsRate: 4, DebtRate: 24, PostingDay: 1}, true},
+		{Interest{SavingsRate: -1, PostingDay: 1}, false},
+		{Interest{DebtRate: 101, PostingDay: 1}, false},
+		{Interest{SavingsRate: 4, PostingDay: 0}, false},
+		{Interest{SavingsRate: 4, PostingDay: 32}, false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateInterest(tt.interest); (err == nil) != tt.ok {
+			t.Errorf("ValidateInterest(%+v) = %v, want ok=%v", tt.interest, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// AmountLink defines a transaction's amount as a percentage of another
+// transaction's amount, such as putting 10% of each paycheck into savings.
+type AmountLink struct {
+	// ID is the ID of the transaction that the amount is based on.
+	ID string `yaml:"id"`
+	// Percent is the percentage of the other transaction's amount (regardless
+	// of whether it's income or an expense). As with the Amount column, the
+	// result is an expense when it's negative, and income when it's
+	// positive.
+	Percent float64 `yaml:"percent"`
+	// Round is what the amount is rounded to, in cents, e.g. 100 for whole
+	// dollars. 0 rounds to the nearest cent.
+	Round int `yaml:"round,omitempty"`
+	// RoundMode is constants.LinkRoundUp or constants.LinkRoundDown to always
+	// round the size of the amount up or down, or empty to round to the
+	// nearest value.
+	RoundMode string `yaml:"roundMode,omitempty"`
+}
+
+// apply returns the link's percentage of source, rounded according to the
+// link's rounding rule.
+func (l *AmountLink) apply(source int) int {
+	size := math.Abs(float64(source)) * math.Abs(l.Percent) / 100
+
+	step := float64(l.Round)
+	if step <= 0 {
+		step = 1
+	}
+
+	switch l.RoundMode {
+	case constants.LinkRoundUp:
+		// a tiny tolerance keeps floating point error from rounding e.g.
+		// 200.00000001 cents up to the next step
+		size = math.Ceil(size/step-1e-9) * step
+	case constants.LinkRoundDown:
+		size = math.Floor(size/step+1e-9) * step
+	default:
+		size = math.Round(size/step) * step
+	}
+
+	if l.Percent < 0 {
+		return -int(size)
+	}
+
+	return int(size)
+}
+
+// String formats the link the way it's shown in the config view, such as
+// "10% of 6e1f... rounded up to $1.00". Percentages without a "+" are
+// expenses.
+func (l AmountLink) String() string {
+	s := fmt.Sprintf(constants.LinkFormat, formatLinkPercent(l.Percent), l.ID)
+
+	if l.Round > 1 || l.RoundMode != "" {
+		round := constants.LinkRounded
+		if l.RoundMode != "" {
+			round = fmt.Sprintf("%v %v", round, l.RoundMode)
+		}
+
+		step := l.Round
+		if step <= 0 {
+			step = 1
+		}
+
+		s = fmt.Sprintf("%v %v %v %v", s, round, constants.ExceptionMoveTo, lib.FormatAsCurrency(step))
+	}
+
+	return s
+}
+
+// formatLinkPercent formats a link's percentage, e.g. "10%" for an expense or
+// "+10%" for income.
+func formatLinkPercent(p float64) string {
+	s := strconv.FormatFloat(math.Abs(p), 'f', -1, 64) + "%"
+	if p > 0 {
+		return "+" + s
+	}
+
+	return s
+}
+
+// GetLinkText formats the transaction's amount link for a single line of
+// text, such as a config view cell. It's empty if the amount isn't linked.
+func GetLinkText(tx *TX) string {
+	if tx.Link == nil {
+		return ""
+	}
+
+	return tx.Link.String()
+}
+
+// ParseAmountLink parses an amount link as entered by the user, such as "10%
+// of <ID>", "+25% of <ID> rounded to $1" or "30% of <ID> rounded up to $5".
+// As with the Amount column, the result is an expense unless the percentage
+// starts with a "+". The source is returned as it was entered, since it may
+// also be a transaction's name; see ResolveLinkID. An empty value means that
+// the amount isn't linked.
+func ParseAmountLink(s string) (*AmountLink, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	l := &AmountLink{}
+
+	p, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSuffix(fields[0], "%"), "+"), "-"), 64)
+	if err != nil || !strings.HasSuffix(fields[0], "%") {
+		return nil, fmt.Errorf("\"%v\" is not a percentage; %v", fields[0], constants.MsgLinkHint)
+	}
+
+	l.Percent = -p
+	if strings.HasPrefix(fields[0], "+") {
+		l.Percent = p
+	}
+
+	if len(fields) < 3 || !strings.EqualFold(fields[1], constants.LinkOf) {
+		return nil, fmt.Errorf("the linked amount is missing the transaction it's a percentage of; %v", constants.MsgLinkHint)
+	}
+
+	// the source runs until the rounding rule, since names can have spaces
+	i := 2
+	source := []string{}
+	for ; i < len(fields) && !strings.EqualFold(fields[i], constants.LinkRounded) && !strings.EqualFold(fields[i], "round"); i++ {
+		source = append(source, fields[i])
+	}
+
+	l.ID = strings.Join(source, " ")
+	if l.ID == "" {
+		return nil, fmt.Errorf("the linked amount is missing the transaction it's a percentage of; %v", constants.MsgLinkHint)
+	}
+
+	if i == len(fields) {
+		return l, nil
+	}
+
+	// the rounding rule, e.g. "rounded up to $5"
+	rest := []string{}
+	for _, field := range fields[i+1:] {
+		rest = append(rest, strings.ToLower(field))
+	}
+
+	if len(rest) > 0 && (rest[0] == constants.LinkRoundUp || rest[0] == constants.LinkRoundDown) {
+		l.RoundMode = rest[0]
+		rest = rest[1:]
+	}
+
+	if len(rest) > 0 && rest[0] == constants.ExceptionMoveTo {
+		rest = rest[1:]
+	}
+
+	switch len(rest) {
+	case 0:
+		l.Round = 100
+	case 1:
+		if !strings.ContainsAny(rest[0], "123456789") {
+			return nil, fmt.Errorf("\"%v\" is not an amount to round to; %v", rest[0], constants.MsgLinkHint)
+		}
+
+		l.Round = int(math.Abs(float64(lib.ParseDollarAmount(rest[0], true))))
+	default:
+		return nil, fmt.Errorf("\"%v\" is not understood in the linked amount's rounding; %v", strings.Join(rest, " "), constants.MsgLinkHint)
+	}
+
+	if l.Round == 1 {
+		l.Round = 0
+	}
+
+	return l, nil
+}
+
+// ResolveLinkID returns the ID of the transaction that ref refers to, which
+// is either its ID or its name (if exactly one transaction has that name,
+// ignoring case).
+func ResolveLinkID(txs []TX, ref string) (string, error) {
+	for i := range txs {
+		if txs[i].ID == ref {
+			return ref, nil
+		}
+	}
+
+	ids := []string{}
+	for i := range txs {
+		if strings.EqualFold(txs[i].Name, ref) {
+			ids = append(ids, txs[i].ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("there's no transaction with the ID or name \"%v\"", ref)
+	case 1:
+		return ids[0], nil
+	}
+
+	return "", fmt.Errorf("%v transactions are named \"%v\", so use the ID of the one to link to instead", len(ids), ref)
+}
+
+// LinkAmounts looks up the source of every linked amount in txs, so that the
+// amounts can be calculated (see TX.GetAmount). Each transaction keeps a copy
+// of its source as it currently is, so this has to be called again whenever
+// the transactions change. Links that are broken or circular are left
+// unresolved, and the transaction's fixed Amount is used instead.
+func LinkAmounts(txs []TX) {
+	byID := make(map[string]*TX)
+	for i := range txs {
+		byID[txs[i].ID] = &txs[i]
+	}
+
+	for i := range txs {
+		txs[i].linkSource = nil
+		if txs[i].Link == nil {
+			continue
+		}
+
+		source, ok := getLinkSource(byID, txs[i].Link.ID, map[string]bool{txs[i].ID: true})
+		if ok {
+			txs[i].linkSource = source
+		}
+	}
+}
+
+// getLinkSource returns a copy of the transaction with the provided ID, with
+// its own link resolved. ok is false if the transaction doesn't exist, or if
+// following the links leads back to one of the transactions in seen.
+func getLinkSource(byID map[string]*TX, id string, seen map[string]bool) (source *TX, ok bool) {
+	tx, found := byID[id]
+	if !found || seen[id] {
+		return nil, false
+	}
+
+	c := *tx
+	c.linkSource = nil
+
+	if tx.Link != nil {
+		seen[id] = true
+		c.linkSource, ok = getLinkSource(byID, tx.Link.ID, seen)
+		delete(seen, id)
+
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return &c, true
+}
+
+// GetLinkedDependents returns every transaction whose amount depends on the
+// transaction with the provided ID, directly or through other links.
+func GetLinkedDependents(txs []TX, id string) []*TX {
+	result := []*TX{}
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for i := range txs {
+			if txs[i].Link == nil || txs[i].Link.ID != current || seen[txs[i].ID] {
+				continue
+			}
+
+			seen[txs[i].ID] = true
+			result = append(result, &txs[i])
+			queue = append(queue, txs[i].ID)
+		}
+	}
+
+	return result
+}
+
+// getLinkProblems reports the links that refer to a transaction that doesn't
+// exist, or that eventually refer back to themselves.
+func getLinkProblems(txs []TX) Problems {
+	problems := Problems{}
+	byID := make(map[string]*TX)
+	for i := range txs {
+		byID[txs[i].ID] = &txs[i]
+	}
+
+	for i := range txs {
+		tx := &txs[i]
+		if tx.Link == nil {
+			continue
+		}
+
+		if _, ok := byID[tx.Link.ID]; !ok {
+			problems = append(problems, Problem{
+				TXID:    tx.ID,
+				Columns: []string{constants.ColumnLink},
+				Message: fmt.Sprintf("The amount is linked to a transaction that doesn't exist (%v), so the fixed amount is used instead.", tx.Link.ID),
+			})
+
+			continue
+		}
+
+		// follow the links until they end, or come back around
+		names := []string{tx.Name}
+		seen := map[string]bool{tx.ID: true}
+
+		for next := byID[tx.Link.ID]; next != nil; {
+			names = append(names, next.Name)
+
+			if next.ID == tx.ID {
+				problems = append(problems, Problem{
+					TXID:    tx.ID,
+					Columns: []string{constants.ColumnLink},
+					Message: fmt.Sprintf("The linked amounts are circular (%v), so the fixed amount is used instead.", strings.Join(names, " → ")),
+				})
+
+				break
+			}
+
+			if seen[next.ID] || next.Link == nil {
+				// another transaction's cycle, which is reported on its own
+				break
+			}
+
+			seen[next.ID] = true
+			next = byID[next.Link.ID]
+		}
+	}
+
+	return problems
+}
+
+// getLinkedAmount returns the amount of a linked transaction on day, and
+// false if the transaction isn't linked (or the link couldn't be resolved).
+func (tx *TX) getLinkedAmount(day time.Time) (int, bool) {
+	if tx.Link == nil || tx.linkSource == nil {
+		return 0, false
+	}
+
+	return tx.Link.apply(tx.linkSource.GetAmount(day)), true
+}
+
+// GetLinkSourceName returns the name of the transaction that the amount is
+// linked to, or an empty string if it isn't linked (or the link couldn't be
+// resolved).
+func (tx *TX) GetLinkSourceName() string {
+	if tx.Link == nil || tx.linkSource == nil {
+		return ""
+	}
+
+	return tx.linkSource.Name
+}
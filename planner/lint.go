@@ -0,0 +1,164 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// lintTooManyFactor is how many times more often than its frequency suggests a
+// transaction has to occur before it's flagged, e.g. a weekly transaction with
+// every weekday checked occurs 7 times as often as expected.
+const lintTooManyFactor = 3
+
+// GetExpectedOccurrences estimates how many times a transaction would occur
+// between start and end (inclusive) based only on its frequency and interval,
+// e.g. about 52 times per year for a weekly transaction. Weekdays are ignored,
+// which is what allows transactions that occur far more often than their
+// frequency suggests to be detected.
+func GetExpectedOccurrences(tx *TX, start, end time.Time) float64 {
+	if starts := tx.GetStartsDate(); !starts.IsZero() && starts.After(start) {
+		start = starts
+	}
+
+	if ends := tx.GetEndsDate(); !ends.IsZero() && ends.Before(end) {
+		end = ends
+	}
+
+	if end.Before(start) {
+		return 0
+	}
+
+	days := end.Sub(start).Hours()/24 + 1
+
+	interval := float64(tx.Interval)
+	if interval <= 0 {
+		interval = 1
+	}
+
+	var period float64
+
+	switch tx.Frequency {
+	case constants.YEARLY:
+		period = 365.25
+	case constants.MONTHLY:
+		period = 365.25 / 12
+	case constants.WEEKLY:
+		period = 7
+	default:
+		// constants.DAILY, or an unrecognized frequency, which the results
+		// also treat as daily
+		period = 1
+	}
+
+	return days / (period * interval)
+}
+
+// Lint runs every transaction through the projection window from start to end
+// and counts how many times each one occurs, keyed by TX ID. Active
+// transactions that never occur, or that occur far more often than their
+// frequency suggests, are returned as problems. holidays are used as in
+// GetResults.
+func Lint(txs []TX, start, end time.Time, holidays Holidays) (map[string]int, Problems) {
+	counts := make(map[string]int, len(txs))
+	problems := Problems{}
+	holidays = getHolidays(holidays, start, end)
+
+	for i := range txs {
+		tx := &txs[i]
+
+		occurrences, err := GetResultOccurrences(tx, start, end, holidays)
+		if err != nil {
+			// the underlying cause is reported by validation
+			continue
+		}
+
+		n := len(occurrences)
+		counts[tx.ID] = n
+
+		if !tx.Active {
+			continue
+		}
+
+		problems = append(problems, getUnusedExceptionProblems(tx, start, end)...)
+
+		if n == 0 {
+			problems = append(problems, getNeverOccursProblem(tx, start, end))
+			continue
+		}
+
+		expected := GetExpectedOccurrences(tx, start, end)
+		if tx.RRule == "" && expected >= 1 && float64(n) > expected*lintTooManyFactor {
+			problems = append(problems, Problem{
+				TXID:    tx.ID,
+				Columns: append([]string{constants.ColumnOccurrences, constants.ColumnFrequency}, constants.Weekdays...),
+				Message: fmt.Sprintf(
+					"This occurs %v times in the projection window, which is far more than expected for a %v transaction (about %v). Check the weekdays.",
+					n,
+					strings.ToLower(tx.Frequency),
+					int(math.Round(expected)),
+				),
+			})
+		}
+	}
+
+	return counts, problems
+}
+
+// getNeverOccursProblem explains why an active transaction never occurs
+// within the projection window, as best as it can.
+func getNeverOccursProblem(tx *TX, start, end time.Time) Problem {
+	p := Problem{
+		TXID:    tx.ID,
+		Columns: []string{constants.ColumnOccurrences},
+	}
+
+	starts := tx.GetStartsDate()
+	ends := tx.GetEndsDate()
+
+	switch {
+	case tx.RRule != "":
+		p.Message = "The recurrence rule never occurs in the projection window."
+	case !ends.IsZero() && ends.Before(start):
+		p.Message = fmt.Sprintf("This ended on %v, before the projection window starts.", tx.GetEndsDateString())
+		p.Columns = append(p.Columns, constants.ColumnEnds)
+	case !starts.IsZero() && starts.After(end):
+		p.Message = fmt.Sprintf("This starts on %v, after the projection window ends.", tx.GetStartDateString())
+		p.Columns = append(p.Columns, constants.ColumnStarts)
+	default:
+		p.Message = "This never occurs in the projection window, so it adds nothing to the results. Check the start date, interval and weekdays."
+		p.Columns = append(p.Columns, constants.ColumnStarts, constants.ColumnInterval)
+	}
+
+	return p
+}
+
+// getUnusedExceptionProblems points out the transaction's exceptions within
+// the projection window that aren't on a day it's scheduled for, since they
+// have no effect.
+func getUnusedExceptionProblems(tx *TX, start, end time.Time) Problems {
+	problems := Problems{}
+
+	for _, e := range tx.Exceptions {
+		day := e.GetDate()
+		if day.IsZero() || day.Before(start) || day.After(end) {
+			continue
+		}
+
+		scheduled, err := tx.getOccurrences(start, day, day)
+		if err != nil || len(scheduled) > 0 {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			TXID:    tx.ID,
+			Columns: []string{constants.ColumnExceptions},
+			Message: fmt.Sprintf("The exception on %v has no effect, since this isn't scheduled to occur on that day.", e.Date),
+		})
+	}
+
+	return problems
+}
@@ -0,0 +1,219 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// maxPaymentDay is the latest day of the month that monthly payments can
+// start on, so that every month has a payment on the same day.
+const maxPaymentDay = 28
+
+// Loan is an amortizing loan, such as a car loan or a mortgage, which is paid
+// off with a fixed monthly payment (plus an optional extra payment). A
+// transaction that was generated from a loan keeps it, so that its schedule
+// can be shown and changed later; see ApplyLoan.
+type Loan struct {
+	// Principal is the amount that's borrowed, in cents.
+	Principal int `yaml:"principal"`
+	// Rate is the annual percentage rate (APR), e.g. 6.5 for 6.5%.
+	Rate float64 `yaml:"rate"`
+	// Term is the number of monthly payments that the loan is paid off in,
+	// without any extra payments.
+	Term int `yaml:"term"`
+	// Start is the day (YYYY-MM-DD) of the first payment.
+	Start string `yaml:"start"`
+	// Extra is paid towards the principal with every payment, in cents.
+	Extra int `yaml:"extra,omitempty"`
+}
+
+// LoanPayment is a single payment in a loan's amortization schedule. The
+// amounts are in cents.
+type LoanPayment struct {
+	Number    int
+	Date      time.Time
+	Payment   int
+	Interest  int
+	Principal int
+	// Balance is the principal that's left after the payment.
+	Balance int
+}
+
+// GetStart returns the day of the loan's first payment, or a zero time if
+// it's not a valid date.
+func (l *Loan) GetStart() time.Time {
+	return parseExceptionDate(l.Start)
+}
+
+// GetPayment returns the loan's regular monthly payment (without the extra
+// payment), which pays it off in exactly Term payments.
+func (l *Loan) GetPayment() int {
+	if l.Term <= 0 {
+		return l.Principal
+	}
+
+	r := l.Rate / 100 / monthsPerYear
+	if r == 0 {
+		return int(math.Ceil(float64(l.Principal) / float64(l.Term)))
+	}
+
+	return int(math.Round(float64(l.Principal) * r / (1 - math.Pow(1+r, -float64(l.Term)))))
+}
+
+// GetSchedule returns the loan's amortization schedule, with the interest and
+// principal of each payment and the balance that's left after it. The last
+// payment pays off whatever is left, so it may be smaller (or, due to
+// rounding, slightly larger) than the others.
+func (l *Loan) GetSchedule() []LoanPayment {
+	schedule := []LoanPayment{}
+	start := l.GetStart()
+	if start.IsZero() || l.Principal <= 0 {
+		return schedule
+	}
+
+	r := l.Rate / 100 / monthsPerYear
+	payment := l.GetPayment() + l.Extra
+	balance := l.Principal
+
+	for i := 0; balance > 0 && i < l.Term; i++ {
+		p := LoanPayment{
+			Number:   i + 1,
+			Date:     start.AddDate(0, i, 0),
+			Interest: int(math.Round(float64(balance) * r)),
+		}
+
+		p.Payment = payment
+		if balance+p.Interest <= payment || i == l.Term-1 {
+			p.Payment = balance + p.Interest
+		}
+
+		p.Principal = p.Payment - p.Interest
+		balance -= p.Principal
+		p.Balance = balance
+
+		schedule = append(schedule, p)
+	}
+
+	return schedule
+}
+
+// GetLoanSummary summarizes the loan's schedule, such as "$-473.51 a month
+// (including $50.00 extra), paid off on Mon 2031-05-01 after 55 payments,
+// with $2,311.20 of interest".
+func (l *Loan) GetLoanSummary() string {
+	schedule := l.GetSchedule()
+	if len(schedule) == 0 {
+		return ""
+	}
+
+	interest := 0
+	for _, p := range schedule {
+		interest += p.Interest
+	}
+
+	last := schedule[len(schedule)-1]
+	payment := lib.FormatAsCurrency(-(l.GetPayment() + l.Extra))
+
+	if l.Extra > 0 {
+		payment = fmt.Sprintf(constants.LoanSummaryExtraFormat, payment, lib.FormatAsCurrency(l.Extra))
+	}
+
+	s := fmt.Sprintf(constants.LoanSummaryFormat, payment, last.Date.Format(constants.DetailDateFormat), len(schedule), lib.FormatAsCurrency(interest))
+
+	if saved := l.Term - len(schedule); saved > 0 {
+		s = fmt.Sprintf(constants.LoanSummarySavedFormat, s, saved)
+	}
+
+	return s
+}
+
+// ValidateLoan checks a loan as entered by the user.
+func ValidateLoan(l Loan) error {
+	if l.Principal <= 0 {
+		return fmt.Errorf("the principal must be more than $0.00")
+	}
+
+	if l.Rate < 0 || l.Rate > 100 {
+		return fmt.Errorf("the interest rate must be between 0%% and 100%%, not %v", formatPercent(l.Rate))
+	}
+
+	if l.Term < 1 {
+		return fmt.Errorf("the term must be at least 1 month")
+	}
+
+	if l.Extra < 0 {
+		return fmt.Errorf("the extra payment can't be negative")
+	}
+
+	_, err := ParseFirstPaymentDate(l.Start)
+
+	return err
+}
+
+// ParseFirstPaymentDate parses the day (YYYY-MM-DD) of the first of a series
+// of monthly payments, such as a loan's, which has to be on one of the first
+// 28 days of the month so that every month has a payment on the same day.
+func ParseFirstPaymentDate(s string) (time.Time, error) {
+	yr, mo, day, err := ParseDate(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the first payment date is invalid: %v", err.Error())
+	}
+
+	if yr == 0 && mo == 0 && day == 0 {
+		return time.Time{}, fmt.Errorf("the first payment date is required")
+	}
+
+	if day > maxPaymentDay {
+		return time.Time{}, fmt.Errorf("the first payment must be on one of the first %v days of the month, so that every month has a payment on the same day", maxPaymentDay)
+	}
+
+	return time.Date(yr, time.Month(mo), day, 0, 0, 0, 0, time.UTC), nil
+}
+
+// ApplyLoan turns tx into the monthly payment of the loan: its amount is the
+// regular payment plus the extra payment, it starts on the first payment and
+// ends on the last one, and a smaller (or larger) last payment is an
+// exception for that date. Any other amount rules are removed, and if tx was
+// already generated from a loan, the exception for its old last payment is
+// removed too.
+func ApplyLoan(tx *TX, l Loan) {
+	schedule := l.GetSchedule()
+	if len(schedule) == 0 {
+		return
+	}
+
+	if tx.Loan != nil {
+		if old := tx.Loan.GetSchedule(); len(old) > 0 {
+			if e := tx.GetException(old[len(old)-1].Date); e != nil && !e.Skip && e.MoveTo == "" {
+				tx.Exceptions = tx.SetException(Exception{Date: e.Date})
+			}
+		}
+	}
+
+	first, last := schedule[0], schedule[len(schedule)-1]
+
+	tx.Amount = -(l.GetPayment() + l.Extra)
+	tx.Frequency = constants.MONTHLY
+	tx.Interval = 1
+	tx.MonthlyRule = ""
+	tx.RRule = ""
+	tx.StartsYear, tx.StartsMonth, tx.StartsDay = first.Date.Year(), int(first.Date.Month()), first.Date.Day()
+	tx.EndsYear, tx.EndsMonth, tx.EndsDay = last.Date.Year(), int(last.Date.Month()), last.Date.Day()
+	tx.SeasonalAmounts = nil
+	tx.AmountChanges = nil
+	tx.Escalation = nil
+	tx.Link = nil
+	tx.Formula = ""
+
+	if last.Payment != -tx.Amount {
+		amount := -last.Payment
+		tx.Exceptions = tx.SetException(Exception{Date: lib.GetNowDateString(last.Date), Amount: &amount})
+	}
+
+	tx.Loan = &l
+}
@@ -0,0 +1,128 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+func TestLoanSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		loan     Loan
+		payment  int
+		payments int
+		last     time.Time
+	}{
+		{
+			name:     "$20,000 at 6% over 5 years",
+			loan:     Loan{Principal: 2000000, Rate: 6, Term: 60, Start: "2026-01-15"},
+			payment:  38666,
+			payments: 60,
+			last:     date(2030, 12, 15),
+		},
+		{
+			name:     "with $100 extra a month",
+			loan:     Loan{Principal: 2000000, Rate: 6, Term: 60, Start: "2026-01-15", Extra: 10000},
+			payment:  38666,
+			payments: 47,
+			last:     date(2029, 11, 15),
+		},
+		{
+			name:     "interest-free",
+			loan:     Loan{Principal: 1200000, Rate: 0, Term: 12, Start: "2026-03-01"},
+			payment:  100000,
+			payments: 12,
+			last:     date(2027, 2, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.loan.GetPayment(); got != tt.payment {
+				t.Errorf("got a payment of %v, want %v", got, tt.payment)
+			}
+
+			schedule := tt.loan.GetSchedule()
+			if len(schedule) != tt.payments {
+				t.Fatalf("got %v payments, want %v", len(schedule), tt.payments)
+			}
+
+			last := schedule[len(schedule)-1]
+			if last.Balance != 0 {
+				t.Errorf("the last payment leaves a balance of %v", last.Balance)
+			}
+
+			if !last.Date.Equal(tt.last) {
+				t.Errorf("the last payment is on %v, want %v", last.Date, tt.last)
+			}
+
+			principal := 0
+			for _, p := range schedule {
+				principal += p.Principal
+
+				if p.Payment != p.Interest+p.Principal {
+					t.Errorf("payment %v of %v isn't its interest %v plus its principal %v", p.Number, p.Payment, p.Interest, p.Principal)
+				}
+			}
+
+			if principal != tt.loan.Principal {
+				t.Errorf("the payments add up to %v of principal, want %v", principal, tt.loan.Principal)
+			}
+		})
+	}
+}
+
+func TestApplyLoan(t *testing.T) {
+	l := Loan{Principal: 2000000, Rate: 6, Term: 60, Start: "2026-01-15"}
+	schedule := l.GetSchedule()
+	last := schedule[len(schedule)-1]
+
+	tx := TX{}
+	ApplyLoan(&tx, l)
+
+	if tx.Amount != -38666 || tx.Frequency != constants.MONTHLY || tx.Interval != 1 {
+		t.Errorf("got an amount of %v every %v %v, want -38666 every 1 MONTHLY", tx.Amount, tx.Interval, tx.Frequency)
+	}
+
+	if got := tx.GetEndsDate(); !got.Equal(last.Date) {
+		t.Errorf("the transaction ends on %v, want %v", got, last.Date)
+	}
+
+	e := tx.GetException(last.Date)
+	if last.Payment == 38666 {
+		if e != nil {
+			t.Errorf("got an exception for a last payment that's the same as the others: %v", e)
+		}
+
+		return
+	}
+
+	if e == nil || e.Amount == nil || *e.Amount != -last.Payment {
+		t.Errorf("got the exception %v for the last payment, want an amount of %v", e, -last.Payment)
+	}
+}
+
+func TestValidateLoan(t *testing.T) {
+	tests := []struct {
+		name string
+		loan Loan
+		ok   bool
+	}{
+		{"valid", Loan{Principal: 100, Rate: 5, Term: 12, Start: "2026-01-28"}, true},
+		{"no principal", Loan{Rate: 5, Term: 12, Start: "2026-01-01"}, false},
+		{"negative rate", Loan{Principal: 100, Rate: -1, Term: 12, Start: "2026-01-01"}, false},
+		{"no term", Loan{Principal: 100, Rate: 5, Start: "2026-01-01"}, false},
+		{"payment day past the 28th", Loan{Principal: 100, Rate: 5, Term: 12, Start: "2026-01-29"}, false},
+		{"no first payment", Loan{Principal: 100, Rate: 5, Term: 12}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateLoan(tt.loan); (err == nil) != tt.ok {
+				t.Errorf("got %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
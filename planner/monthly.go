@@ -0,0 +1,151 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	"github.com/teambition/rrule-go"
+)
+
+// MonthlyRule is a variant of the monthly recurrence, such as "the first
+// Monday" or "the last business day" of the month. Without one, a monthly
+// transaction recurs on the same day of the month as its start date.
+type MonthlyRule struct {
+	// Nth is 1 to 4 for the first to fourth, or -1 for the last.
+	Nth int
+	// Day is a weekday such as constants.WeekdayMonday, or one of
+	// constants.MonthlyRuleDay or constants.MonthlyRuleBusinessDay.
+	Day string
+}
+
+// String formats the rule the way it's shown in the config view, such as
+// "first Monday" or "last business day". This is also how it's saved.
+func (r MonthlyRule) String() string {
+	return fmt.Sprintf("%v %v", GetMonthlyRuleOrdinal(r.Nth), r.Day)
+}
+
+// GetMonthlyRuleOrdinal returns the word for the Nth value of a monthly rule,
+// such as "first" for 1 and "last" for -1.
+func GetMonthlyRuleOrdinal(nth int) string {
+	if nth < 0 {
+		return constants.MonthlyRuleLast
+	}
+
+	if nth >= 1 && nth <= len(constants.MonthlyRuleOrdinals) {
+		return constants.MonthlyRuleOrdinals[nth-1]
+	}
+
+	return fmt.Sprint(nth)
+}
+
+// parseMonthlyRuleOrdinal accepts "first", "1st", "1", "last" and so on.
+func parseMonthlyRuleOrdinal(s string) (int, bool) {
+	if s == constants.MonthlyRuleLast {
+		return -1, true
+	}
+
+	for i, ordinal := range constants.MonthlyRuleOrdinals {
+		n := i + 1
+		short := fmt.Sprint(n)
+
+		switch s {
+		case ordinal, short, short + getOrdinalSuffix(n):
+			return n, true
+		}
+	}
+
+	return 0, false
+}
+
+func getOrdinalSuffix(n int) string {
+	switch n {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
+
+// parseMonthlyRuleDay accepts a weekday (or its first 3 letters), "day", or
+// "business day".
+func parseMonthlyRuleDay(s string) (string, bool) {
+	switch strings.Join(strings.Fields(s), " ") {
+	case constants.MonthlyRuleDay:
+		return constants.MonthlyRuleDay, true
+	case constants.MonthlyRuleBusinessDay, "businessday", "business", "workday", "work day":
+		return constants.MonthlyRuleBusinessDay, true
+	}
+
+	for _, weekday := range constants.Weekdays {
+		if s == strings.ToLower(weekday) || s == strings.ToLower(weekday[:3]) {
+			return weekday, true
+		}
+	}
+
+	return "", false
+}
+
+// ParseMonthlyRule parses a monthly rule as entered by the user, such as
+// "first Monday", "2nd tue", "last day" or "last business day". The input is
+// case-insensitive. An empty value means that there's no rule, which is
+// returned as nil.
+func ParseMonthlyRule(s string) (*MonthlyRule, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return nil, nil
+	}
+
+	invalid := fmt.Errorf("\"%v\" is not a recognized monthly rule; %v", s, constants.MsgMonthlyRuleHint)
+
+	ordinal, rest, found := strings.Cut(s, " ")
+	if !found {
+		return nil, invalid
+	}
+
+	nth, ok := parseMonthlyRuleOrdinal(ordinal)
+	if !ok {
+		return nil, invalid
+	}
+
+	day, ok := parseMonthlyRuleDay(strings.TrimSpace(rest))
+	if !ok {
+		return nil, invalid
+	}
+
+	return &MonthlyRule{Nth: nth, Day: day}, nil
+}
+
+// GetMonthlyRule returns the transaction's monthly rule, or nil if it doesn't
+// have one (or it can't be parsed, which validation points out).
+func (tx *TX) GetMonthlyRule() *MonthlyRule {
+	r, err := ParseMonthlyRule(tx.MonthlyRule)
+	if err != nil {
+		return nil
+	}
+
+	return r
+}
+
+// apply narrows a monthly recurrence down to the days that match the rule.
+func (r *MonthlyRule) apply(rr *rrule.ROption) {
+	switch r.Day {
+	case constants.MonthlyRuleDay:
+		rr.Bymonthday = []int{r.Nth}
+	case constants.MonthlyRuleBusinessDay:
+		rr.Byweekday = []rrule.Weekday{rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR}
+		rr.Bysetpos = []int{r.Nth}
+	default:
+		all := []rrule.Weekday{rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA, rrule.SU}
+		for i, weekday := range constants.Weekdays {
+			if weekday == r.Day {
+				rr.Byweekday = []rrule.Weekday{all[i].Nth(r.Nth)}
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+func TestParseMonthlyRule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"first monday", "first Monday"},
+		{"2nd tue", "second Tuesday"},
+		{"  Last Day ", "last day"},
+		{"last business day", "last business day"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseMonthlyRule(tt.in)
+		if err != nil {
+			t.Fatalf("ParseMonthlyRule(%q) returned an error: %v", tt.in, err)
+		}
+
+		if got := r.String(); got != tt.want {
+			t.Errorf("ParseMonthlyRule(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if r, err := ParseMonthlyRule(""); r != nil || err != nil {
+		t.Errorf("ParseMonthlyRule(\"\") = %v, %v, want no rule", r, err)
+	}
+
+	for _, s := range []string{"monday", "fifth monday", "first someday", "last"} {
+		if _, err := ParseMonthlyRule(s); err == nil {
+			t.Errorf("ParseMonthlyRule(%q) didn't return an error", s)
+		}
+	}
+}
+
+func TestGetOccurrencesMonthlyRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		// 2026-02-01 and 2026-03-01 are Sundays
+		{"first Monday", "2026-01-05 2026-02-02 2026-03-02 2026-04-06 2026-05-04 2026-06-01"},
+		{"second Tuesday", "2026-01-13 2026-02-10 2026-03-10 2026-04-14 2026-05-12 2026-06-09"},
+		{"last day", "2026-01-31 2026-02-28 2026-03-31 2026-04-30 2026-05-31 2026-06-30"},
+		// 2026-01-31, 2026-02-28 and 2026-05-31 are weekends
+		{"last business day", "2026-01-30 2026-02-27 2026-03-31 2026-04-30 2026-05-29 2026-06-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			tx := TX{}
+			tx.Frequency = constants.MONTHLY
+			tx.Interval = 1
+			tx.MonthlyRule = tt.rule
+			tx.StartsYear, tx.StartsMonth, tx.StartsDay = 2026, 1, 1
+
+			days, err := GetOccurrences(&tx, date(2026, 1, 1), date(2026, 6, 30))
+			if err != nil {
+				t.Fatalf("GetOccurrences returned an error: %v", err)
+			}
+
+			if got := formatDays(days); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOccurrencesMonthlyRuleEvery2Months(t *testing.T) {
+	tx := TX{}
+	tx.Frequency = constants.MONTHLY
+	tx.Interval = 2
+	tx.MonthlyRule = "first Monday"
+	tx.StartsYear, tx.StartsMonth, tx.StartsDay = 2026, 1, 1
+
+	days, err := GetOccurrences(&tx, date(2026, 1, 1), date(2026, 6, 30))
+	if err != nil {
+		t.Fatalf("GetOccurrences returned an error: %v", err)
+	}
+
+	if got, want := formatDays(days), "2026-01-05 2026-03-02 2026-05-04"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+}
@@ -0,0 +1,290 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/teambition/rrule-go"
+)
+
+// GetStartsDate returns the transaction's start date, or a zero time if it
+// hasn't been set (0-0-0).
+func (tx *TX) GetStartsDate() time.Time {
+	if tx.StartsYear == 0 && tx.StartsMonth == 0 && tx.StartsDay == 0 {
+		return time.Time{}
+	}
+
+	return time.Date(tx.StartsYear, time.Month(tx.StartsMonth), tx.StartsDay, 0, 0, 0, 0, time.UTC)
+}
+
+// GetEndsDate returns the transaction's end date, or a zero time if it hasn't
+// been set (0-0-0).
+func (tx *TX) GetEndsDate() time.Time {
+	if tx.EndsYear == 0 && tx.EndsMonth == 0 && tx.EndsDay == 0 {
+		return time.Time{}
+	}
+
+	return time.Date(tx.EndsYear, time.Month(tx.EndsMonth), tx.EndsDay, 0, 0, 0, 0, time.UTC)
+}
+
+// GetRRuleWeekdays converts the transaction's checked weekdays into values
+// that the rrule library accepts, in order from Monday to Sunday.
+func (tx *TX) GetRRuleWeekdays() []rrule.Weekday {
+	all := []rrule.Weekday{rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA, rrule.SU}
+	weekdays := []rrule.Weekday{}
+
+	for i, weekday := range all {
+		if tx.Weekdays[i] {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+
+	return weekdays
+}
+
+// GetOccurrences returns every date that the transaction recurs on between
+// start and end (inclusive), regardless of whether it's active. A recurrence
+// rule (tx.RRule) overrides the frequency, interval, weekdays and monthly
+// rule. The dates are as scheduled, before any exceptions or moving off of
+// weekends and holidays; see GetResultOccurrences, which is what GetResults is
+// based on.
+func GetOccurrences(tx *TX, start time.Time, end time.Time) ([]time.Time, error) {
+	return tx.getOccurrences(start, start, end)
+}
+
+// getOccurrences is GetOccurrences, except that a transaction without a start
+// date recurs from anchor rather than from start. This allows occurrences
+// from just outside of the projection window to be looked up without changing
+// which days the transaction falls on.
+func (tx *TX) getOccurrences(anchor, start, end time.Time) ([]time.Time, error) {
+	if tx.RRule != "" {
+		return tx.getRRuleOccurrences(anchor, start, end)
+	}
+
+	startsDate := tx.GetStartsDate()
+	endsDate := tx.GetEndsDate()
+
+	if endsDate.IsZero() || endsDate.After(end) {
+		endsDate = end
+	}
+
+	if startsDate.IsZero() {
+		startsDate = anchor
+	}
+
+	rr := rrule.ROption{
+		Dtstart:  startsDate,
+		Until:    endsDate,
+		Interval: tx.Interval,
+	}
+
+	switch tx.Frequency {
+	case rrule.YEARLY.String():
+		rr.Freq = rrule.YEARLY
+	case rrule.MONTHLY.String():
+		rr.Freq = rrule.MONTHLY
+
+		if r := tx.GetMonthlyRule(); r != nil {
+			r.apply(&rr)
+		}
+	case rrule.DAILY.String():
+		// daily transactions occur every Interval days, regardless of the
+		// weekdays; see NormalizeFrequencies
+		rr.Freq = rrule.DAILY
+	default:
+		// weekly transactions (and unrecognized frequencies) occur daily on
+		// the checked weekdays, or every day if none are checked
+		rr.Freq = rrule.DAILY
+		rr.Byweekday = tx.GetRRuleWeekdays()
+	}
+
+	s, err := rrule.NewRRule(rr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct rrule for tx %v: %v", tx.Name, err.Error())
+	}
+
+	return s.Between(start, end, true), nil
+}
+
+// GetNextOccurrences returns up to n of the transaction's occurrences between
+// start and end (inclusive), as they appear in the results; see
+// GetResultOccurrences.
+func GetNextOccurrences(tx *TX, start time.Time, end time.Time, n int, holidays Holidays) ([]Occurrence, error) {
+	all, err := GetResultOccurrences(tx, start, end, holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(all) > n {
+		all = all[:n]
+	}
+
+	return all, nil
+}
+
+// Occurrence is a day that a transaction occurs on in the results.
+type Occurrence struct {
+	Date time.Time
+	// Scheduled is the day that the transaction was scheduled for, which is
+	// different from Date if it was moved off of a weekend or holiday, or by
+	// an exception.
+	Scheduled time.Time
+	// Amount is the transaction's amount as of the day it was scheduled for,
+	// unless an exception changed it.
+	Amount int
+	// Exception is the exception that applies to this occurrence, if any.
+	Exception *Exception
+}
+
+// Moved returns true if the occurrence isn't on the day it was scheduled for.
+func (o Occurrence) Moved() bool {
+	return !o.Date.Equal(o.Scheduled)
+}
+
+// GetOccurrenceNote describes how an occurrence differs from the transaction's
+// schedule, such as "moved from Sun 2026-11-01, $-250.00 instead of $-80.00",
+// or returns an empty string if it doesn't.
+func GetOccurrenceNote(tx *TX, o Occurrence) string {
+	notes := []string{}
+
+	if o.Moved() {
+		notes = append(notes, fmt.Sprintf(constants.OccurrenceMovedFrom, o.Scheduled.Format(constants.DetailDateFormat)))
+	}
+
+	if amount := tx.GetAmount(o.Scheduled); o.Amount != amount {
+		notes = append(notes, fmt.Sprintf(constants.OccurrenceAmountChanged, lib.FormatAsCurrency(o.Amount), lib.FormatAsCurrency(amount)))
+	}
+
+	return strings.Join(notes, ", ")
+}
+
+// GetResultOccurrences returns every day that the transaction occurs on
+// between start and end (inclusive), as they appear in the results: its
+// exceptions are applied, and the occurrences that fall on a weekend or
+// holiday are moved according to its weekend/holiday policy (tx.Shift). This
+// is what GetResults is based on, so the dates shown anywhere in the UI always
+// agree with the results. If holidays is nil, the US federal holidays are
+// used.
+func GetResultOccurrences(tx *TX, start, end time.Time, holidays Holidays) ([]Occurrence, error) {
+	return tx.getResultOccurrences(start, start, end, holidays, false)
+}
+
+// getResultOccurrences is GetResultOccurrences, except that a transaction
+// without a start date recurs from anchor (see getOccurrences), optionally
+// including the occurrences that are skipped by an exception, which are on
+// the day they were scheduled for.
+func (tx *TX) getResultOccurrences(anchor, start, end time.Time, holidays Holidays, includeSkipped bool) ([]Occurrence, error) {
+	// occurrences just outside of the window may be moved into it
+	days, err := tx.getOccurrences(anchor, start.AddDate(0, 0, -maxShiftDays), end.AddDate(0, 0, maxShiftDays))
+	if err != nil {
+		return nil, err
+	}
+
+	// exceptions can move occurrences from anywhere into the window
+	for i := range tx.Exceptions {
+		e := &tx.Exceptions[i]
+		day := e.GetDate()
+		to := e.GetMoveTo()
+
+		if day.IsZero() || to.IsZero() || to.Before(start) || to.After(end) {
+			continue
+		}
+
+		if !day.Before(start.AddDate(0, 0, -maxShiftDays)) && !day.After(end.AddDate(0, 0, maxShiftDays)) {
+			continue
+		}
+
+		scheduled, err := tx.getOccurrences(anchor, day, day)
+		if err == nil && len(scheduled) > 0 {
+			days = append(days, day)
+		}
+	}
+
+	if tx.Shift != "" {
+		holidays = getHolidays(holidays, start, end)
+	}
+
+	occurrences := []Occurrence{}
+
+	for _, day := range days {
+		o := Occurrence{
+			Date:      holidays.Shift(day, tx.Shift),
+			Scheduled: day,
+			Amount:    tx.GetAmount(day),
+			Exception: tx.GetException(day),
+		}
+
+		if e := o.Exception; e != nil {
+			if e.Skip {
+				if !includeSkipped || day.Before(start) || day.After(end) {
+					continue
+				}
+
+				o.Date = day
+			}
+
+			if e.Amount != nil {
+				o.Amount = *e.Amount
+			}
+
+			if to := e.GetMoveTo(); !to.IsZero() && !e.Skip {
+				o.Date = to
+			}
+		}
+
+		if o.Date.Before(start) || o.Date.After(end) {
+			continue
+		}
+
+		occurrences = append(occurrences, o)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Date.Before(occurrences[j].Date) })
+
+	return occurrences, nil
+}
+
+// DayOccurrence is an occurrence of a transaction on a particular day.
+type DayOccurrence struct {
+	TX         *TX
+	Occurrence Occurrence
+	// Skipped is true if the occurrence was scheduled for the day, but an
+	// exception skips it.
+	Skipped bool
+}
+
+// GetDayOccurrences returns the occurrences of every active transaction on
+// the provided day, including the ones that are skipped, so that each of them
+// can be changed. start is the start of the projection, which transactions
+// without a start date recur from, as in GetResults.
+func GetDayOccurrences(txs []TX, start, day time.Time, holidays Holidays) []DayOccurrence {
+	result := []DayOccurrence{}
+
+	for i := range txs {
+		tx := &txs[i]
+		if !tx.Active {
+			continue
+		}
+
+		occurrences, err := tx.getResultOccurrences(start, day, day, holidays, true)
+		if err != nil {
+			continue
+		}
+
+		for _, o := range occurrences {
+			result = append(result, DayOccurrence{
+				TX:         tx,
+				Occurrence: o,
+				Skipped:    o.Exception != nil && o.Exception.Skip,
+			})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetOccurrencesDailyAndWeekly(t *testing.T) {
+	// 2026-01-05 is a Monday
+	start, end := date(2026, 1, 5), date(2026, 1, 18)
+
+	tests := []struct {
+		name      string
+		frequency string
+		interval  int
+		weekdays  map[int]bool
+		want      int
+	}{
+		{"daily", constants.DAILY, 1, nil, 14},
+		{"every 3 days", constants.DAILY, 3, nil, 5},
+		{"daily ignores weekdays", constants.DAILY, 1, map[int]bool{0: true}, 14},
+		{"weekly on Mondays", constants.WEEKLY, 1, map[int]bool{0: true}, 2},
+		{"weekly on weekdays", constants.WEEKLY, 1, map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true}, 10},
+		{"weekly without weekdays", constants.WEEKLY, 1, map[int]bool{}, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := TX{}
+			tx.Frequency = tt.frequency
+			tx.Interval = tt.interval
+			tx.Weekdays = tt.weekdays
+
+			got, err := GetOccurrences(&tx, start, end)
+			if err != nil {
+				t.Fatalf("GetOccurrences returned an error: %v", err)
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("got %v occurrences, want %v: %v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeFrequenciesClearsDailyWeekdays(t *testing.T) {
+	txs := []TX{{}, {}}
+	txs[0].Frequency = "d"
+	txs[0].Weekdays = map[int]bool{0: true}
+	txs[1].Frequency = "weekly"
+	txs[1].Weekdays = map[int]bool{0: true}
+
+	NormalizeFrequencies(txs)
+
+	if txs[0].Frequency != constants.DAILY || txs[0].Weekdays[0] {
+		t.Errorf("daily transaction was normalized to %v with weekdays %v", txs[0].Frequency, txs[0].Weekdays)
+	}
+
+	if txs[1].Frequency != constants.WEEKLY || !txs[1].Weekdays[0] {
+		t.Errorf("weekly transaction was normalized to %v with weekdays %v", txs[1].Frequency, txs[1].Weekdays)
+	}
+}
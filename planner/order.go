@@ -0,0 +1,177 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetNextOrder returns the manual order value that a transaction appended to
+// the end of txs should receive.
+func GetNextOrder(txs []TX) int {
+	highest := 0
+	for i := range txs {
+		if txs[i].Order > highest {
+			highest = txs[i].Order
+		}
+	}
+
+	return highest + 1
+}
+
+// ParseOrder parses the Order column as entered by the user, which must be a
+// positive whole number.
+func ParseOrder(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("\"%v\" is not a valid order; enter a whole number of 1 or more", strings.TrimSpace(s))
+	}
+
+	return n, nil
+}
+
+// NormalizeOrder renumbers the manual order of every transaction to 1..n,
+// preserving the current manual ordering. Transactions without an assigned
+// order are placed after the ones that have one, oldest first.
+func NormalizeOrder(txs []TX) {
+	sorted := getManuallyOrdered(txs)
+
+	orders := make(map[string]int, len(sorted))
+	for i := range sorted {
+		orders[sorted[i].ID] = i + 1
+	}
+
+	for i := range txs {
+		txs[i].Order = orders[txs[i].ID]
+	}
+}
+
+// getManuallyOrdered returns a copy of txs in their manual order.
+// Transactions without an assigned order come after the ones that have one,
+// oldest first.
+func getManuallyOrdered(txs []TX) []TX {
+	sorted := make([]TX, len(txs))
+	copy(sorted, txs)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		oi, oj := sorted[i].Order, sorted[j].Order
+		if (oi == 0) != (oj == 0) {
+			return oj == 0
+		}
+
+		if oi != oj {
+			return oi < oj
+		}
+
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	return sorted
+}
+
+// GetManuallyOrderedIDs returns ids in the manual order of the transactions
+// that they belong to, regardless of how txs is currently sorted. IDs that
+// don't belong to any of txs are left out.
+func GetManuallyOrderedIDs(txs []TX, ids []string) []string {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	result := []string{}
+	for _, tx := range getManuallyOrdered(txs) {
+		if wanted[tx.ID] {
+			result = append(result, tx.ID)
+		}
+	}
+
+	return result
+}
+
+// ApplyVisibleOrder takes the IDs of the visible transactions in the order the
+// user wants them to appear, and rewrites the manual order of txs to match.
+// Transactions that aren't visible (such as hidden inactive ones) keep their
+// position in the manual order relative to the visible slots around them,
+// even if txs is currently sorted by something else. The slice itself is
+// reordered to reflect the new manual order.
+func ApplyVisibleOrder(txs []TX, visibleIDs []string) {
+	visible := make(map[string]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+
+	byID := make(map[string]TX, len(txs))
+	for i := range txs {
+		byID[txs[i].ID] = txs[i]
+	}
+
+	next := 0
+	result := make([]TX, 0, len(txs))
+
+	for _, tx := range getManuallyOrdered(txs) {
+		if !visible[tx.ID] || next >= len(visibleIDs) {
+			result = append(result, tx)
+			continue
+		}
+
+		result = append(result, byID[visibleIDs[next]])
+		next++
+	}
+
+	for i := range result {
+		result[i].Order = i + 1
+	}
+
+	copy(txs, result)
+}
+
+// MoveToOrder moves the transaction with the provided ID to the given
+// position (starting from 1) in the manual order, and renumbers every
+// transaction's order to 1..n, as if it had been dragged there. Positions past
+// the end move it to the end. The slice itself is reordered to reflect the new
+// manual order.
+func MoveToOrder(txs []TX, id string, position int) {
+	ids := []string{}
+	for _, tx := range getManuallyOrdered(txs) {
+		if tx.ID != id {
+			ids = append(ids, tx.ID)
+		}
+	}
+
+	if len(ids) == len(txs) {
+		return
+	}
+
+	i := min(max(position-1, 0), len(ids))
+	ids = append(ids[:i], append([]string{id}, ids[i:]...)...)
+
+	ApplyVisibleOrder(txs, ids)
+}
+
+// MoveIDs moves every selected ID in ids by one position; a negative delta
+// moves them up and a positive delta moves them down. Selected IDs that are
+// already at the edge of the list (or blocked by another selected ID at the
+// edge) stay where they are, so that a block of selected rows keeps its shape.
+func MoveIDs(ids []string, selected map[string]bool, delta int) []string {
+	result := make([]string, len(ids))
+	copy(result, ids)
+
+	if delta < 0 {
+		for i := 1; i < len(result); i++ {
+			if selected[result[i]] && !selected[result[i-1]] {
+				result[i], result[i-1] = result[i-1], result[i]
+			}
+		}
+
+		return result
+	}
+
+	for i := len(result) - 2; i >= 0; i-- {
+		if selected[result[i]] && !selected[result[i+1]] {
+			result[i], result[i+1] = result[i+1], result[i]
+		}
+	}
+
+	return result
+}
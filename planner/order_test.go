@@ -0,0 +1,94 @@
+package planner
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newOrderedTXs returns transactions with the provided IDs, ordered manually
+// in the same order.
+func newOrderedTXs(ids ...string) []TX {
+	txs := []TX{}
+	for i, id := range ids {
+		tx := TX{}
+		tx.ID = id
+		tx.Order = i + 1
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
+// getOrderText returns the IDs of txs in their manual order, along with their
+// orders, such as "a1 b2 c3", regardless of how the slice is sorted.
+func getOrderText(txs []TX) string {
+	parts := []string{}
+	for _, tx := range getManuallyOrdered(txs) {
+		parts = append(parts, tx.ID+strconv.Itoa(tx.Order))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestMoveToOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		position int
+		want     string
+	}{
+		{"to the top", "c", 1, "c1 a2 b3 d4"},
+		{"onto an existing order", "a", 3, "b1 c2 a3 d4"},
+		{"past the end", "b", 999, "a1 c2 d3 b4"},
+		{"same place", "b", 2, "a1 b2 c3 d4"},
+		{"unknown ID", "x", 1, "a1 b2 c3 d4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs := newOrderedTXs("a", "b", "c", "d")
+			MoveToOrder(txs, tt.id, tt.position)
+
+			if got := getOrderText(txs); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveWithinSortedView(t *testing.T) {
+	// the slice (and the view) is sorted in reverse, but moving "c" up should
+	// only swap it with "b" in the manual order
+	txs := newOrderedTXs("a", "b", "c", "d")
+	txs[0], txs[1], txs[2], txs[3] = txs[3], txs[2], txs[1], txs[0]
+
+	ids := GetManuallyOrderedIDs(txs, []string{"d", "c", "b", "a"})
+	ApplyVisibleOrder(txs, MoveIDs(ids, map[string]bool{"c": true}, -1))
+
+	if got, want := getOrderText(txs), "a1 c2 b3 d4"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestApplyVisibleOrderKeepsHiddenRows(t *testing.T) {
+	// "b" is hidden, so it stays between the first two visible slots
+	txs := newOrderedTXs("a", "b", "c", "d")
+	ApplyVisibleOrder(txs, []string{"d", "c", "a"})
+
+	if got, want := getOrderText(txs), "d1 b2 c3 a4"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	for _, s := range []string{"0", "-1", "abc", ""} {
+		if _, err := ParseOrder(s); err == nil {
+			t.Errorf("ParseOrder(%q) didn't return an error", s)
+		}
+	}
+
+	if n, err := ParseOrder(" 3 "); err != nil || n != 3 {
+		t.Errorf("ParseOrder(\" 3 \") = %v, %v, want 3", n, err)
+	}
+}
@@ -0,0 +1,209 @@
+package planner
+
+import (
+	"sort"
+	"time"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// PeriodBill is an expense that's due in a pay period.
+type PeriodBill struct {
+	ID   string
+	Name string
+	Date time.Time
+	// Amount is the expense's amount, which is negative.
+	Amount int
+}
+
+// PayPeriod is the time from one paycheck until the day before the next one.
+// The amounts are in cents.
+type PayPeriod struct {
+	// Payday is the day of the paycheck that starts the period, or a zero time
+	// for the days before the first paycheck.
+	Payday time.Time
+	Start  time.Time
+	// End is the day before the next paycheck, or the last day of the results.
+	End time.Time
+	// Income is all of the period's income, including the paycheck.
+	Income int
+	// Bills are the period's expenses, in the order that they're due.
+	Bills []PeriodBill
+	// Committed is the total of the bills, which is negative.
+	Committed int
+	// Balance is the balance at the end of the period.
+	Balance int
+	// Suggestion is a bill that could be paid from the previous paycheck
+	// instead, if the period runs short; see GetPayPeriods.
+	Suggestion *PeriodBill
+}
+
+// GetLeft returns the discretionary amount that's left of the period's income
+// after its bills.
+func (p *PayPeriod) GetLeft() int {
+	return p.Income + p.Committed
+}
+
+// IsShort returns true if the period's bills add up to more than its income.
+func (p *PayPeriod) IsShort() bool {
+	return p.GetLeft() < 0
+}
+
+// GetPayPeriods splits the results into periods that each start on a payday
+// of the income transaction with the provided ID, listing the bills that are
+// due before the next paycheck. The days before the first paycheck are a
+// period of their own, if they have any income or bills. Sweeps and interest
+// aren't transactions, so they aren't counted.
+//
+// When a period runs short and the previous one has money left over, the bill
+// that's suggested to be moved to the previous paycheck is the smallest one
+// that makes up the shortfall and still fits in what's left of it, or failing
+// that, the largest one that fits.
+func GetPayPeriods(txs []TX, incomeID string, results []lib.Result, holidays Holidays) []PayPeriod {
+	periods := []PayPeriod{}
+	if len(results) == 0 {
+		return periods
+	}
+
+	start, end := toDay(results[0].Date), toDay(results[len(results)-1].Date)
+	holidays = getHolidays(holidays, start, end)
+	LinkAmounts(txs)
+
+	type dayOccurrence struct {
+		tx *TX
+		o  Occurrence
+	}
+
+	paydays := []time.Time{}
+	occurrences := []dayOccurrence{}
+
+	for i := range txs {
+		tx := &txs[i]
+		if !tx.Active {
+			continue
+		}
+
+		days, err := GetResultOccurrences(tx, start, end, holidays)
+		if err != nil {
+			continue
+		}
+
+		for _, o := range days {
+			if tx.ID == incomeID && (len(paydays) == 0 || !paydays[len(paydays)-1].Equal(o.Date)) {
+				paydays = append(paydays, o.Date)
+			}
+
+			occurrences = append(occurrences, dayOccurrence{tx: tx, o: o})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].o.Date.Before(occurrences[j].o.Date)
+	})
+
+	// each period's start, with the days before the first payday (if any)
+	// starting the first period
+	starts := []time.Time{}
+	if len(paydays) == 0 || paydays[0].After(start) {
+		starts = append(starts, start)
+	}
+
+	starts = append(starts, paydays...)
+
+	for i, s := range starts {
+		p := PayPeriod{Start: s, End: end}
+		if i+1 < len(starts) {
+			p.End = starts[i+1].AddDate(0, 0, -1)
+		}
+
+		if len(paydays) > 0 && !s.Before(paydays[0]) {
+			p.Payday = s
+		}
+
+		periods = append(periods, p)
+	}
+
+	for _, d := range occurrences {
+		i := sort.Search(len(periods), func(i int) bool {
+			return periods[i].End.After(d.o.Date) || periods[i].End.Equal(d.o.Date)
+		})
+		if i == len(periods) {
+			continue
+		}
+
+		p := &periods[i]
+		if d.o.Amount >= 0 {
+			p.Income += d.o.Amount
+			continue
+		}
+
+		p.Committed += d.o.Amount
+		p.Bills = append(p.Bills, PeriodBill{
+			ID:     d.tx.ID,
+			Name:   d.tx.Name,
+			Date:   d.o.Date,
+			Amount: d.o.Amount,
+		})
+	}
+
+	byDay := make(map[string]int)
+	for _, r := range results {
+		byDay[lib.GetNowDateString(r.Date)] = r.Balance
+	}
+
+	for i := range periods {
+		periods[i].Balance = byDay[lib.GetNowDateString(periods[i].End)]
+	}
+
+	// drop a period before the first payday that has nothing in it
+	if len(periods) > 1 && periods[0].Payday.IsZero() && periods[0].Income == 0 && len(periods[0].Bills) == 0 {
+		periods = periods[1:]
+	}
+
+	for i := 1; i < len(periods); i++ {
+		p, prev := &periods[i], &periods[i-1]
+		if !p.IsShort() || prev.Payday.IsZero() || prev.GetLeft() <= 0 {
+			continue
+		}
+
+		p.Suggestion = getPayPeriodSuggestion(p.Bills, -p.GetLeft(), prev.GetLeft())
+	}
+
+	return periods
+}
+
+// getPayPeriodSuggestion picks a bill to move to the previous paycheck, which
+// has spare left over; see GetPayPeriods. It returns nil if none of the bills
+// fit.
+func getPayPeriodSuggestion(bills []PeriodBill, shortfall, spare int) *PeriodBill {
+	var best *PeriodBill
+
+	for i := range bills {
+		b := &bills[i]
+		size := -b.Amount
+
+		if size > spare {
+			continue
+		}
+
+		switch {
+		case best == nil:
+			best = b
+		case -best.Amount < shortfall:
+			// nothing makes up the shortfall yet, so bigger is better
+			if size > -best.Amount {
+				best = b
+			}
+		case size >= shortfall && size < -best.Amount:
+			best = b
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	s := *best
+
+	return &s
+}
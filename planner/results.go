@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// GetResults calculates the day-by-day balance from start to end (inclusive)
+// for every active transaction, in the same form as lib.GetResults. Unlike the
+// lib, the occurrences of each transaction come from GetOccurrences, so that
+// recurrence features that only this application supports (such as monthly
+// rules) are honoured, and so that the results always agree with the dates
+// shown elsewhere in the UI. Transactions with an invalid recurrence rule are
+// left out.
+//
+// Each transaction's exceptions are applied, and its occurrences are moved off
+// of weekends and the provided holidays according to its weekend/holiday
+// policy (the US federal holidays are used if holidays is nil). Occurrences
+// that differ from the schedule are noted in the day's transaction names,
+// e.g. "Rent (moved from Sat 2026-11-01)".
+//
+// Interest accrues on the balance at the end of each day, after the day's
+// transactions, and is posted on its posting day (see Interest). Then the
+// sweep rules are evaluated in order against the balance. Posted interest and
+// each sweep are counted in the day's income or expenses and listed in its
+// transaction names. interest may be nil.
+func GetResults(txs []TX, start, end time.Time, startBalance int, holidays Holidays, sweeps []SweepRule, interest *Interest) ([]lib.Result, error) {
+	if start.After(end) {
+		return []lib.Result{}, fmt.Errorf("start date is after end date: %v vs %v", start, end)
+	}
+
+	results := []lib.Result{}
+	byDay := make(map[int64]int)
+
+	for dt, i := start, 0; !dt.After(end); dt, i = dt.AddDate(0, 0, 1), i+1 {
+		byDay[dt.Unix()] = len(results)
+		results = append(results, lib.Result{Record: i, Date: dt})
+	}
+
+	holidays = getHolidays(holidays, start, end)
+	LinkAmounts(txs)
+
+	for i := range txs {
+		tx := &txs[i]
+		if !tx.Active {
+			continue
+		}
+
+		occurrences, err := GetResultOccurrences(tx, start, end, holidays)
+		if err != nil {
+			// this can only be an invalid recurrence rule, which is
+			// reported by validation
+			continue
+		}
+
+		for _, o := range occurrences {
+			j, ok := byDay[o.Date.Unix()]
+			if !ok {
+				continue
+			}
+
+			r := &results[j]
+
+			if o.Amount >= 0 {
+				r.DayIncome += o.Amount
+			} else {
+				r.DayExpenses += o.Amount
+			}
+
+			r.DayNet += o.Amount
+			r.DayTransactionNamesSlice = append(r.DayTransactionNamesSlice, getOccurrenceName(tx, o))
+		}
+	}
+
+	balance := startBalance
+	income := 0
+	expenses := 0
+	diff := 0
+	accrued := 0.0
+
+	for i := range results {
+		r := &results[i]
+
+		balance, accrued = applyInterest(interest, r, balance+r.DayNet, accrued)
+		balance = applySweeps(sweeps, r, balance)
+		income += r.DayIncome
+		expenses += r.DayExpenses
+		diff += r.DayNet
+
+		r.DayTransactionNames = strings.Join(r.DayTransactionNamesSlice, "; ")
+		r.Balance = balance
+		r.CumulativeIncome = income
+		r.CumulativeExpenses = expenses
+		r.DiffFromStart = diff
+	}
+
+	return results, nil
+}
+
+// getOccurrenceName is how an occurrence of a transaction is listed in the
+// results, which notes how it differs from the transaction's schedule, if it
+// does.
+func getOccurrenceName(tx *TX, o Occurrence) string {
+	note := GetOccurrenceNote(tx, o)
+	if note == "" {
+		return tx.Name
+	}
+
+	return fmt.Sprintf(constants.OccurrenceNoteFormat, tx.Name, note)
+}
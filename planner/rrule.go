@@ -0,0 +1,189 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	"github.com/teambition/rrule-go"
+)
+
+// rrulePreviewLimit caps how many dates are generated when looking for the
+// upcoming occurrences of a recurrence rule, so that e.g. a secondly rule
+// can't freeze the UI.
+const rrulePreviewLimit = 100000
+
+// NormalizeRRule tidies up an iCalendar (RFC 5545) recurrence rule as entered
+// by the user, and returns it with one property per line, which is how it's
+// saved. Properties can be separated by newlines or spaces (which is how
+// they're shown in the config view), and a lone rule such as
+// "FREQ=MONTHLY;BYMONTHDAY=15" is accepted without the "RRULE:" prefix. A
+// DTSTART is moved to the first line, since the rules before it would
+// otherwise ignore it.
+func NormalizeRRule(s string) string {
+	dtstart := []string{}
+	lines := []string{}
+
+	for _, line := range strings.Fields(s) {
+		if !strings.Contains(line, ":") || strings.Contains(getRRulePropertyName(line), "=") {
+			line = "RRULE:" + line
+		}
+
+		if strings.EqualFold(getRRulePropertyName(line), "DTSTART") {
+			dtstart = append(dtstart, line)
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(append(dtstart, lines...), "\n")
+}
+
+// getRRulePropertyName returns the name of the property on a line of a
+// recurrence rule, such as "DTSTART" for "DTSTART;TZID=UTC:20200229T000000".
+// For a lone rule such as "FREQ=MONTHLY;BYMONTHDAY=15", it's the rule's first
+// part.
+func getRRulePropertyName(line string) string {
+	name, _, _ := strings.Cut(line, ":")
+	name, _, _ = strings.Cut(name, ";")
+
+	return name
+}
+
+// hasDTStart returns true if a normalized recurrence rule (see NormalizeRRule)
+// has a DTSTART property on any of its lines.
+func hasDTStart(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		if strings.EqualFold(getRRulePropertyName(line), "DTSTART") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseRRule parses an iCalendar (RFC 5545) recurrence rule, which can include
+// DTSTART, RRULE, RDATE and EXDATE properties. If it has no DTSTART, dtstart is
+// used instead. Rules that recur more often than daily are rejected, since the
+// results only go down to the day and expanding e.g. a secondly rule over the
+// projection window would freeze the UI.
+func ParseRRule(s string, dtstart time.Time) (*rrule.Set, error) {
+	s = NormalizeRRule(s)
+	if s == "" {
+		return nil, errors.New("the recurrence rule is empty")
+	}
+
+	set, err := rrule.StrToRRuleSet(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %v", err.Error())
+	}
+
+	if set.GetRRule() == nil && len(set.GetRDate()) == 0 {
+		return nil, errors.New(constants.MsgRRuleHint)
+	}
+
+	if r := set.GetRRule(); r != nil && r.OrigOptions.Freq > rrule.DAILY {
+		return nil, fmt.Errorf("a recurrence rule can't be %v, since the results only go down to the day; use FREQ=DAILY or less often", r.OrigOptions.Freq.String())
+	}
+
+	if !hasDTStart(s) {
+		set.DTStart(dtstart)
+	}
+
+	return set, nil
+}
+
+// getRRuleStart is the DTSTART for a transaction's recurrence rule when the
+// rule doesn't specify one; fallback is used if the TX has no start date.
+func (tx *TX) getRRuleStart(fallback time.Time) time.Time {
+	if starts := tx.GetStartsDate(); !starts.IsZero() {
+		return starts
+	}
+
+	return fallback
+}
+
+// toDay strips the time of day (and time zone) from a recurrence, since the
+// results only go down to the day.
+func toDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// getRRuleOccurrences returns the days that the transaction's recurrence rule
+// occurs on between start and end (inclusive). The transaction's start and
+// end dates still apply. If neither the rule nor the transaction has a start
+// date, the rule starts from anchor.
+func (tx *TX) getRRuleOccurrences(anchor, start, end time.Time) ([]time.Time, error) {
+	set, err := ParseRRule(tx.RRule, tx.getRRuleStart(anchor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to process rrule for tx %v: %v", tx.Name, err.Error())
+	}
+
+	if starts := tx.GetStartsDate(); !starts.IsZero() && starts.After(start) {
+		start = starts
+	}
+
+	if ends := tx.GetEndsDate(); !ends.IsZero() && ends.Before(end) {
+		end = ends
+	}
+
+	days := []time.Time{}
+
+	if end.Before(start) {
+		return days, nil
+	}
+
+	// occurrences with a time of day still count on their last day
+	for _, dt := range set.Between(start, end.AddDate(0, 0, 1), true) {
+		day := toDay(dt)
+		if day.After(end) || (len(days) > 0 && days[len(days)-1].Equal(day)) {
+			continue
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// GetRRulePreview returns up to n of the upcoming days (from the provided day
+// onwards) that a recurrence rule would occur on if it were used for tx. This
+// allows a rule to be previewed before it's applied.
+func GetRRulePreview(tx *TX, rule string, from time.Time, n int) ([]time.Time, error) {
+	set, err := ParseRRule(rule, tx.getRRuleStart(from))
+	if err != nil {
+		return nil, err
+	}
+
+	if starts := tx.GetStartsDate(); !starts.IsZero() && starts.After(from) {
+		from = starts
+	}
+
+	ends := tx.GetEndsDate()
+	days := []time.Time{}
+	next := set.Iterator()
+
+	for i := 0; i < rrulePreviewLimit && len(days) < n; i++ {
+		dt, ok := next()
+		if !ok {
+			break
+		}
+
+		day := toDay(dt)
+		if day.Before(from) || (len(days) > 0 && days[len(days)-1].Equal(day)) {
+			continue
+		}
+
+		if !ends.IsZero() && day.After(ends) {
+			break
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
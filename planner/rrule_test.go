@@ -0,0 +1,134 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeRRule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"FREQ=MONTHLY;BYMONTHDAY=15", "RRULE:FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO EXDATE:20260105T000000Z", "RRULE:FREQ=WEEKLY;BYDAY=MO\nEXDATE:20260105T000000Z"},
+		{"RRULE:FREQ=YEARLY DTSTART:20200229T000000Z", "DTSTART:20200229T000000Z\nRRULE:FREQ=YEARLY"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRRule(tt.in); got != tt.want {
+			t.Errorf("NormalizeRRule(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// formatDays formats days as a space-separated list of dates.
+func formatDays(days []time.Time) string {
+	s := []string{}
+	for _, d := range days {
+		s = append(s, d.Format(time.DateOnly))
+	}
+
+	return strings.Join(s, " ")
+}
+
+func TestGetRRuleOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		rrule string
+		start time.Time
+		want  string
+	}{
+		{
+			name:  "15th of each month",
+			rrule: "FREQ=MONTHLY;BYMONTHDAY=15",
+			start: date(2026, 1, 1),
+			want:  "2026-01-15 2026-02-15 2026-03-15",
+		},
+		{
+			name:  "last Friday of each month",
+			rrule: "FREQ=MONTHLY;BYDAY=-1FR",
+			start: date(2026, 1, 1),
+			want:  "2026-01-30 2026-02-27 2026-03-27",
+		},
+		{
+			name:  "every other Monday from the DTSTART, wherever it's entered",
+			rrule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO DTSTART:20260112T000000Z",
+			start: date(2026, 1, 1),
+			want:  "2026-01-12 2026-01-26 2026-02-09 2026-02-23 2026-03-09 2026-03-23",
+		},
+		{
+			name:  "an excluded date",
+			rrule: "RRULE:FREQ=MONTHLY;BYMONTHDAY=1 EXDATE:20260201T000000Z",
+			start: date(2026, 1, 1),
+			want:  "2026-01-01 2026-03-01",
+		},
+		{
+			name:  "only extra dates",
+			rrule: "RDATE:20260110T000000Z,20260320T000000Z",
+			start: date(2026, 1, 1),
+			want:  "2026-01-10 2026-03-20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := TX{}
+			tx.RRule = NormalizeRRule(tt.rrule)
+
+			days, err := tx.getRRuleOccurrences(tt.start, tt.start, date(2026, 3, 31))
+			if err != nil {
+				t.Fatalf("getRRuleOccurrences returned an error: %v", err)
+			}
+
+			if got := formatDays(days); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRRuleOccurrencesKeepsStartAndEnd(t *testing.T) {
+	tx := TX{}
+	tx.RRule = "RRULE:FREQ=MONTHLY;BYMONTHDAY=15"
+	tx.StartsYear, tx.StartsMonth, tx.StartsDay = 2026, 2, 1
+	tx.EndsYear, tx.EndsMonth, tx.EndsDay = 2026, 4, 1
+
+	days, err := tx.getRRuleOccurrences(date(2026, 1, 1), date(2026, 1, 1), date(2026, 12, 31))
+	if err != nil {
+		t.Fatalf("getRRuleOccurrences returned an error: %v", err)
+	}
+
+	if got, want := formatDays(days), "2026-02-15 2026-03-15"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=SOMETIMES",
+		"EXDATE:20260101T000000Z",
+		"FREQ=HOURLY",
+		"FREQ=SECONDLY;INTERVAL=10",
+	} {
+		if _, err := ParseRRule(s, date(2026, 1, 1)); err == nil {
+			t.Errorf("ParseRRule(%q) didn't return an error", s)
+		}
+	}
+}
+
+func TestGetRRulePreview(t *testing.T) {
+	tx := TX{}
+
+	days, err := GetRRulePreview(&tx, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", date(2026, 1, 1), 2)
+	if err != nil {
+		t.Fatalf("GetRRulePreview returned an error: %v", err)
+	}
+
+	if got, want := formatDays(days), "2028-02-29 2032-02-29"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// SafeToSpend is how much can be spent today without the projected balance
+// going below a minimum later on. The amounts are in cents.
+type SafeToSpend struct {
+	// Amount is what's safe to spend, which is never negative.
+	Amount int
+	// Today is the first day of the results from now onwards, and Balance is
+	// the balance at the end of it.
+	Today   time.Time
+	Balance int
+	// Lowest is the lowest projected balance from Today onwards, which is on
+	// Date (the earliest such day). Spending anything today lowers every
+	// balance after it by the same amount, so this is the day that
+	// constrains the amount.
+	Lowest int
+	Date   time.Time
+	// Minimum is the balance that shouldn't be gone below.
+	Minimum int
+}
+
+// GetDrawdown returns how far the balance falls from Today's until Date.
+func (s *SafeToSpend) GetDrawdown() int {
+	return s.Balance - s.Lowest
+}
+
+// GetSafeToSpend calculates how much can be spent from the balance of the
+// first day of the results from now onwards: that balance minus the largest
+// drawdown below it, minus the minimum balance, which is the same as the
+// lowest balance from then on minus the minimum. ok is false if the results
+// end before now.
+func GetSafeToSpend(results []lib.Result, minimum int, now time.Time) (s SafeToSpend, ok bool) {
+	today := toDay(now)
+
+	for i := range results {
+		r := &results[i]
+		if toDay(r.Date).Before(today) {
+			continue
+		}
+
+		if !ok {
+			ok = true
+			s = SafeToSpend{
+				Today:   r.Date,
+				Balance: r.Balance,
+				Lowest:  r.Balance,
+				Date:    r.Date,
+				Minimum: minimum,
+			}
+		}
+
+		if r.Balance < s.Lowest {
+			s.Lowest = r.Balance
+			s.Date = r.Date
+		}
+	}
+
+	s.Amount = max(s.Lowest-minimum, 0)
+
+	return s, ok
+}
+
+// GetSummary returns the safe-to-spend amount as it's shown in the header
+// bar, such as "Safe to spend: $1,234.56".
+func (s *SafeToSpend) GetSummary() string {
+	return fmt.Sprintf(constants.SafeToSpendFormat, lib.FormatAsCurrency(s.Amount))
+}
+
+// GetBreakdown explains how the safe-to-spend amount was calculated, with
+// one line per step, ending with the date that constrains it.
+func (s *SafeToSpend) GetBreakdown() string {
+	lines := []string{
+		fmt.Sprintf(constants.SafeToSpendBalanceFormat, s.Today.Format(constants.DetailDateFormat), lib.FormatAsCurrency(s.Balance)),
+		fmt.Sprintf(constants.SafeToSpendLowestFormat, lib.FormatAsCurrency(s.Lowest), s.Date.Format(constants.DetailDateFormat), lib.FormatAsCurrency(s.GetDrawdown())),
+		fmt.Sprintf(constants.SafeToSpendMinimumFormat, lib.FormatAsCurrency(s.Minimum)),
+	}
+
+	switch {
+	case s.Lowest < s.Minimum:
+		lines = append(lines, fmt.Sprintf(constants.SafeToSpendBelowFormat, lib.FormatAsCurrency(s.Minimum-s.Lowest), s.Date.Format(constants.DetailDateFormat)))
+	case s.Date.Equal(s.Today):
+		lines = append(lines, constants.SafeToSpendToday)
+	default:
+		lines = append(lines, fmt.Sprintf(constants.SafeToSpendConstrainedFormat, s.Date.Format(constants.DetailDateFormat)))
+	}
+
+	return strings.Join(lines, "\n")
+}
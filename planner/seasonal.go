@@ -0,0 +1,138 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// monthsPerYear is the number of values in a seasonal amount table.
+const monthsPerYear = 12
+
+// HasSeasonalAmounts returns true if the transaction's amount depends on the
+// month, rather than being its fixed Amount.
+func (tx *TX) HasSeasonalAmounts() bool {
+	return len(tx.SeasonalAmounts) == monthsPerYear
+}
+
+// GetSeasonalAmount returns the transaction's amount for the month of day,
+// which is its fixed Amount if it doesn't have a seasonal amount table.
+func (tx *TX) GetSeasonalAmount(day time.Time) int {
+	if !tx.HasSeasonalAmounts() {
+		return tx.Amount
+	}
+
+	return tx.SeasonalAmounts[day.Month()-1]
+}
+
+// GetSeasonalRange returns the smallest and largest amounts in the
+// transaction's seasonal amount table, by size, so that for an expense the
+// cheapest month comes first.
+func (tx *TX) GetSeasonalRange() (lo, hi int) {
+	if !tx.HasSeasonalAmounts() {
+		return tx.Amount, tx.Amount
+	}
+
+	lo, hi = tx.SeasonalAmounts[0], tx.SeasonalAmounts[0]
+	for _, a := range tx.SeasonalAmounts[1:] {
+		if math.Abs(float64(a)) < math.Abs(float64(lo)) {
+			lo = a
+		}
+
+		if math.Abs(float64(a)) > math.Abs(float64(hi)) {
+			hi = a
+		}
+	}
+
+	return lo, hi
+}
+
+// GetAmountText formats the transaction's amount the way it's shown in the
+// Amount column, which is a range such as "varies ($-60.00–$-210.00)" if it
+// has a seasonal amount table, or today's amount and where it comes from
+// (e.g. "$-200.00 (10% of Paycheck)") if it's linked to another transaction.
+func GetAmountText(tx *TX) string {
+	if name := tx.GetLinkSourceName(); name != "" {
+		return fmt.Sprintf(constants.LinkedAmountFormat, lib.FormatAsCurrency(tx.GetAmount(toDay(time.Now()))), formatLinkPercent(tx.Link.Percent), name)
+	}
+
+	if !tx.HasSeasonalAmounts() {
+		return lib.FormatAsCurrency(tx.Amount)
+	}
+
+	lo, hi := tx.GetSeasonalRange()
+
+	return fmt.Sprintf(constants.AmountVariesFormat, lib.FormatAsCurrency(lo), lib.FormatAsCurrency(hi))
+}
+
+// GetSeasonalAmountsText formats the transaction's seasonal amount table for a
+// single line of text, such as a config view cell, e.g. "Jan $-180.00; Feb
+// $-160.00; ...". It's empty if the transaction doesn't have one.
+func GetSeasonalAmountsText(tx *TX) string {
+	if !tx.HasSeasonalAmounts() {
+		return ""
+	}
+
+	entries := []string{}
+	for i, a := range tx.SeasonalAmounts {
+		entries = append(entries, fmt.Sprintf("%v %v", time.Month(i + 1).String()[:3], FormatExceptionAmount(a)))
+	}
+
+	return strings.Join(entries, constants.ExceptionSeparator+" ")
+}
+
+// ParseSeasonalAmounts parses a seasonal amount table as entered by the user,
+// which is twelve amounts from January to December separated by semicolons,
+// each optionally preceded by the month's name, such as "Jan $-180; Feb
+// $-160; ...". As with the Amount column, amounts are expenses unless they
+// start with a "+". An empty value means that there's no table.
+func ParseSeasonalAmounts(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	entries := strings.Split(s, constants.ExceptionSeparator)
+	if len(entries) != monthsPerYear {
+		return nil, fmt.Errorf("a seasonal amount table needs %v amounts, but %v were entered; %v", monthsPerYear, len(entries), constants.MsgSeasonalAmountsHint)
+	}
+
+	amounts := []int{}
+
+	for i, entry := range entries {
+		fields := strings.Fields(entry)
+		month := time.Month(i + 1).String()
+
+		if len(fields) == 2 && (strings.EqualFold(fields[0], month[:3]) || strings.EqualFold(fields[0], month)) {
+			fields = fields[1:]
+		}
+
+		if len(fields) != 1 || !strings.ContainsAny(fields[0], "0123456789") {
+			return nil, fmt.Errorf("\"%v\" is not a valid amount for %v; %v", strings.TrimSpace(entry), month, constants.MsgSeasonalAmountsHint)
+		}
+
+		amounts = append(amounts, ParseExceptionAmount(fields[0]))
+	}
+
+	return amounts, nil
+}
+
+// getAverageAmount returns the average of the transaction's amounts in effect
+// now and at the start of each of the following eleven months, which accounts
+// for its seasonal amount table and any amount changes or escalation.
+func (tx *TX) getAverageAmount(now time.Time) float64 {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	day := toDay(now)
+	total := 0
+
+	for i := 1; i <= monthsPerYear; i++ {
+		total += tx.GetAmount(day)
+		day = first.AddDate(0, i, 0)
+	}
+
+	return float64(total) / monthsPerYear
+}
@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// maxShiftDays is how far an occurrence can be moved to find a business day.
+// It's also how far outside of the projection window occurrences are looked
+// up, since they may be moved into it.
+const maxShiftDays = 14
+
+// ParseShift parses the weekend/holiday policy as entered by the user, such as
+// "next", "previous business day" or "none". The input is case-insensitive.
+// It returns one of constants.ShiftPrevious, constants.ShiftNext, or an empty
+// string for no shifting.
+func ParseShift(s string) (string, error) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	s = strings.TrimSuffix(s, " "+constants.MonthlyRuleBusinessDay)
+
+	switch s {
+	case "", constants.None, "no", "don't move":
+		return "", nil
+	case constants.ShiftPrevious, "prev", "p", "before", "earlier":
+		return constants.ShiftPrevious, nil
+	case constants.ShiftNext, "n", "after", "later":
+		return constants.ShiftNext, nil
+	}
+
+	return "", fmt.Errorf("\"%v\" is not a recognized weekend/holiday policy; %v", s, constants.MsgShiftHint)
+}
+
+// GetShiftText formats a weekend/holiday policy the way it's shown in the
+// config view, such as "next business day".
+func GetShiftText(shift string) string {
+	if shift == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%v %v", shift, constants.MonthlyRuleBusinessDay)
+}
+
+// Shift moves day to the previous or next business day (according to shift,
+// which is one of constants.ShiftPrevious or constants.ShiftNext) if it's a
+// weekend or holiday. Any other shift leaves day as it is.
+func (h Holidays) Shift(day time.Time, shift string) time.Time {
+	delta := 0
+
+	switch shift {
+	case constants.ShiftPrevious:
+		delta = -1
+	case constants.ShiftNext:
+		delta = 1
+	default:
+		return day
+	}
+
+	for i := 0; i < maxShiftDays && !h.IsBusinessDay(day); i++ {
+		day = day.AddDate(0, 0, delta)
+	}
+
+	return day
+}
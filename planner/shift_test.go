@@ -0,0 +1,111 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+func TestParseShift(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"none", ""},
+		{"Next Business Day", constants.ShiftNext},
+		{"after", constants.ShiftNext},
+		{"previous  business day", constants.ShiftPrevious},
+		{"prev", constants.ShiftPrevious},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseShift(tt.in)
+		if err != nil {
+			t.Fatalf("ParseShift(%q) returned an error: %v", tt.in, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseShift(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := ParseShift("sideways"); err == nil {
+		t.Error("ParseShift(\"sideways\") didn't return an error")
+	}
+}
+
+func TestHolidaysShift(t *testing.T) {
+	// 2026-07-03 is a Friday, and the observed Independence Day
+	holidays := Holidays{date(2026, 7, 3): "Independence Day"}
+
+	tests := []struct {
+		day   time.Time
+		shift string
+		want  time.Time
+	}{
+		{date(2026, 1, 31), constants.ShiftNext, date(2026, 2, 2)},
+		{date(2026, 1, 31), constants.ShiftPrevious, date(2026, 1, 30)},
+		{date(2026, 1, 31), "", date(2026, 1, 31)},
+		{date(2026, 1, 29), constants.ShiftNext, date(2026, 1, 29)},
+		{date(2026, 7, 3), constants.ShiftNext, date(2026, 7, 6)},
+		{date(2026, 7, 4), constants.ShiftPrevious, date(2026, 7, 2)},
+	}
+
+	for _, tt := range tests {
+		if got := holidays.Shift(tt.day, tt.shift); !got.Equal(tt.want) {
+			t.Errorf("Shift(%v, %q) = %v, want %v", tt.day.Format(time.DateOnly), tt.shift, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestGetResultOccurrencesShiftsWeekends(t *testing.T) {
+	// 2026-02-01 and 2026-03-01 are Sundays
+	tests := []struct {
+		name  string
+		shift string
+		start time.Time
+		want  []time.Time
+	}{
+		{"next business day", constants.ShiftNext, date(2026, 2, 1), []time.Time{date(2026, 2, 2), date(2026, 3, 2)}},
+		{"previous business day", constants.ShiftPrevious, date(2026, 1, 15), []time.Time{date(2026, 1, 30), date(2026, 2, 27)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := TX{}
+			tx.Frequency = constants.MONTHLY
+			tx.Interval = 1
+			tx.Amount = -180000
+			tx.Shift = tt.shift
+			tx.StartsYear, tx.StartsMonth, tx.StartsDay = 2026, 1, 1
+
+			occurrences, err := GetResultOccurrences(&tx, tt.start, date(2026, 3, 15), Holidays{})
+			if err != nil {
+				t.Fatalf("GetResultOccurrences returned an error: %v", err)
+			}
+
+			if len(occurrences) != len(tt.want) {
+				t.Fatalf("got %v occurrences, want %v: %v", len(occurrences), len(tt.want), occurrences)
+			}
+
+			for i, o := range occurrences {
+				scheduled := date(2026, time.Month(i+2), 1)
+
+				if !o.Date.Equal(tt.want[i]) || !o.Scheduled.Equal(scheduled) {
+					t.Errorf("occurs on %v (scheduled for %v), want %v (scheduled for %v)", o.Date, o.Scheduled, tt.want[i], scheduled)
+				}
+
+				if o.Amount != tx.Amount {
+					t.Errorf("the amount is %v, want %v", o.Amount, tx.Amount)
+				}
+
+				want := "moved from " + scheduled.Format(constants.DetailDateFormat)
+				if got := GetOccurrenceNote(&tx, o); got != want {
+					t.Errorf("the note is %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// sinkingFundYears is how far ahead the next occurrence of an irregular bill
+// is looked for, which covers bills that recur up to every few years.
+const sinkingFundYears = 5
+
+// SinkingFund is the monthly amount to set aside for a bill that doesn't
+// recur every month, such as a yearly insurance premium, so that the money is
+// there by the time it's due. The amounts are in cents.
+type SinkingFund struct {
+	Bill TX
+	// NextDue is the day of the bill's next occurrence from the first
+	// transfer onwards.
+	NextDue time.Time
+	// Amount is the size of the bill on NextDue.
+	Amount int
+	// Transfers is the number of monthly transfers from the first one up to
+	// and including NextDue.
+	Transfers int
+	// Monthly is the level amount to set aside each month to cover the bill
+	// by NextDue.
+	Monthly int
+	// Steady is the amount to set aside each month after NextDue, which is
+	// the bill's average monthly cost.
+	Steady int
+	// Funded is true if there are already sinking fund transactions for the
+	// bill; see TX.SinkingFund.
+	Funded bool
+
+	// scheduled is the day that NextDue was scheduled for, before it was
+	// moved off of a weekend or holiday, or by an exception.
+	scheduled time.Time
+}
+
+// IsIrregularExpense returns true if tx is an active expense that recurs less
+// often than every month, such as a yearly or every-2-years bill. Sinking fund
+// transactions are never irregular expenses themselves.
+func IsIrregularExpense(tx *TX, now time.Time) bool {
+	if !tx.Active || tx.SinkingFund != "" {
+		return false
+	}
+
+	yearly := GetYearlyOccurrences(tx, now)
+
+	return yearly > 0 && yearly < monthsPerYear && GetYearlyAmount(tx, now) < 0
+}
+
+// getMonthsBetween returns the number of monthly transfers from first up to
+// and including day, which is at least one.
+func getMonthsBetween(first, day time.Time) int {
+	months := (day.Year()-first.Year())*monthsPerYear + int(day.Month()) - int(first.Month())
+	if day.Day() >= first.Day() {
+		months++
+	}
+
+	return max(months, 1)
+}
+
+// GetSinkingFunds calculates a sinking fund for each irregular expense (see
+// IsIrregularExpense) in txs, with monthly transfers starting on first. The
+// monthly amount spreads the bill's next occurrence evenly over the transfers
+// until it's due, rounded up to the cent. start is the start of the
+// projection, which bills without a start date recur from, as in GetResults.
+// They're sorted by when the bills are next due.
+func GetSinkingFunds(txs []TX, start, first time.Time, holidays Holidays) []SinkingFund {
+	funds := []SinkingFund{}
+	last := first.AddDate(sinkingFundYears, 0, 0)
+	holidays = getHolidays(holidays, first, last)
+	LinkAmounts(txs)
+
+	funded := make(map[string]bool)
+	for i := range txs {
+		if txs[i].SinkingFund != "" {
+			funded[txs[i].SinkingFund] = true
+		}
+	}
+
+	for i := range txs {
+		tx := &txs[i]
+		if !IsIrregularExpense(tx, first) {
+			continue
+		}
+
+		occurrences, err := tx.getResultOccurrences(start, first, last, holidays, false)
+		if err != nil {
+			continue
+		}
+
+		for _, o := range occurrences {
+			if o.Amount >= 0 {
+				continue
+			}
+
+			f := SinkingFund{
+				Bill:      *tx,
+				NextDue:   o.Date,
+				Amount:    -o.Amount,
+				Transfers: getMonthsBetween(first, o.Date),
+				Steady:    int(math.Ceil(float64(-GetYearlyAmount(tx, o.Date)) / monthsPerYear)),
+				Funded:    funded[tx.ID],
+				scheduled: o.Scheduled,
+			}
+
+			f.Monthly = int(math.Ceil(float64(f.Amount) / float64(f.Transfers)))
+			funds = append(funds, f)
+
+			break
+		}
+	}
+
+	sort.SliceStable(funds, func(i, j int) bool {
+		return funds[i].NextDue.Before(funds[j].NextDue)
+	})
+
+	return funds
+}
+
+// GetSinkingFundTXs returns two transactions for each sinking fund that isn't
+// already funded: a monthly transfer into the fund, starting on first, which
+// steps down (or up) to the steady amount after the bill's next occurrence,
+// and a withdrawal that takes the bill's amount back out of the fund on the
+// bill's own schedule, starting from that occurrence. The bill itself stays
+// as it is, so that the balance only goes down by the monthly transfers. The
+// transactions are placed after txs in the manual ordering.
+func GetSinkingFundTXs(funds []SinkingFund, first, now time.Time, txs []TX) []TX {
+	result := []TX{}
+	all := append([]TX{}, txs...)
+
+	for _, f := range funds {
+		if f.Funded {
+			continue
+		}
+
+		bill := f.Bill
+
+		transfer := GetNewTX(now, all)
+		transfer.Name = fmt.Sprintf(constants.SinkingFundNameFormat, bill.Name)
+		transfer.Note = fmt.Sprintf(constants.SinkingFundNoteFormat, lib.FormatAsCurrency(f.Amount), f.NextDue.Format(constants.DetailDateFormat))
+		transfer.Amount = -f.Monthly
+		transfer.Frequency = constants.MONTHLY
+		transfer.Interval = 1
+		transfer.StartsYear, transfer.StartsMonth, transfer.StartsDay = first.Year(), int(first.Month()), first.Day()
+		transfer.EndsYear, transfer.EndsMonth, transfer.EndsDay = bill.EndsYear, bill.EndsMonth, bill.EndsDay
+		transfer.SinkingFund = bill.ID
+
+		if f.Steady != f.Monthly {
+			transfer.AmountChanges = []AmountChange{{
+				From:   lib.GetNowDateString(first.AddDate(0, f.Transfers, 0)),
+				Amount: -f.Steady,
+			}}
+		}
+
+		result = append(result, transfer)
+		all = append(all, transfer)
+
+		// the withdrawal follows the bill's schedule, including its
+		// exceptions, with the amounts reversed
+		withdrawal := GetNewTX(now, all)
+		withdrawal.Name = fmt.Sprintf(constants.SinkingFundWithdrawalNameFormat, bill.Name)
+		withdrawal.Note = transfer.Note
+		withdrawal.Amount = -bill.Amount
+		withdrawal.Frequency = bill.Frequency
+		withdrawal.Interval = bill.Interval
+		withdrawal.Weekdays = bill.Weekdays
+		withdrawal.RRule = bill.RRule
+		withdrawal.MonthlyRule = bill.MonthlyRule
+		withdrawal.Shift = bill.Shift
+		withdrawal.StartsYear, withdrawal.StartsMonth, withdrawal.StartsDay = bill.StartsYear, bill.StartsMonth, bill.StartsDay
+		withdrawal.EndsYear, withdrawal.EndsMonth, withdrawal.EndsDay = bill.EndsYear, bill.EndsMonth, bill.EndsDay
+		withdrawal.Link = &AmountLink{ID: bill.ID, Percent: 100}
+		withdrawal.SinkingFund = bill.ID
+
+		for _, e := range bill.Exceptions {
+			if e.Amount != nil {
+				amount := -*e.Amount
+				e.Amount = &amount
+			}
+
+			withdrawal.Exceptions = append(withdrawal.Exceptions, e)
+		}
+
+		// earlier occurrences of the bill aren't covered by the fund, but a
+		// recurrence rule is anchored on its start date, so it's kept
+		if o := f.scheduled; withdrawal.RRule == "" && withdrawal.GetStartsDate().Before(o) {
+			withdrawal.StartsYear, withdrawal.StartsMonth, withdrawal.StartsDay = o.Year(), int(o.Month()), o.Day()
+		}
+
+		result = append(result, withdrawal)
+		all = append(all, withdrawal)
+	}
+
+	return result
+}
+
+// GetSinkingFundBalances returns the balance of each bill's sinking fund on
+// each day from start to end (inclusive), formatted for the results view,
+// such as "Car insurance $300.00; Property tax $1,250.00". Transfers into a
+// fund (see TX.SinkingFund) add to it, and withdrawals take from it, starting
+// from when the fund's transactions start, even if that's before start.
+// start is also the start of the projection, which transactions without a
+// start date recur from.
+func GetSinkingFundBalances(txs []TX, start, end time.Time, holidays Holidays) []string {
+	start, end = toDay(start), toDay(end)
+	LinkAmounts(txs)
+
+	names := make(map[string]string)
+	for i := range txs {
+		names[txs[i].ID] = txs[i].Name
+	}
+
+	// the change to each fund on each day
+	ids := []string{}
+	changes := make(map[string]map[string]int)
+	balances := make(map[string]int)
+
+	for i := range txs {
+		tx := &txs[i]
+		if tx.SinkingFund == "" || !tx.Active {
+			continue
+		}
+
+		if _, ok := changes[tx.SinkingFund]; !ok {
+			ids = append(ids, tx.SinkingFund)
+			changes[tx.SinkingFund] = make(map[string]int)
+		}
+
+		from := start
+		if starts := tx.GetStartsDate(); !starts.IsZero() && starts.Before(from) {
+			from = starts
+		}
+
+		occurrences, err := tx.getResultOccurrences(start, from, end, getHolidays(holidays, from, end), false)
+		if err != nil {
+			continue
+		}
+
+		for _, o := range occurrences {
+			if o.Date.Before(start) {
+				balances[tx.SinkingFund] -= o.Amount
+				continue
+			}
+
+			changes[tx.SinkingFund][lib.GetNowDateString(o.Date)] -= o.Amount
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		return names[ids[i]] < names[ids[j]]
+	})
+
+	days := []string{}
+	if len(ids) == 0 {
+		return days
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		key := lib.GetNowDateString(day)
+		parts := []string{}
+
+		for _, id := range ids {
+			balances[id] += changes[id][key]
+
+			name, ok := names[id]
+			if !ok {
+				name = id
+			}
+
+			parts = append(parts, fmt.Sprintf("%v %v", name, lib.FormatAsCurrency(balances[id])))
+		}
+
+		days = append(days, strings.Join(parts, constants.SinkingFundBalanceSeparator))
+	}
+
+	return days
+}
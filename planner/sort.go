@@ -0,0 +1,226 @@
+package planner
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// SortKey is one level of a (possibly multi-column) sort of the config view.
+// Column is one of the config column names, such as constants.ColumnAmount.
+type SortKey struct {
+	Column string `yaml:"column"`
+	Desc   bool   `yaml:"desc"`
+}
+
+// GetNextSortKeys determines the sort keys that result from the user clicking
+// on the header of column. A plain click sorts by that column alone, cycling
+// ascending -> descending -> unsorted. An additive click (ctrl+click) keeps the
+// existing keys and either appends the column as the lowest-priority key, or
+// cycles its direction in place, removing it after descending.
+func GetNextSortKeys(keys []SortKey, column string, additive bool) []SortKey {
+	if !additive {
+		if len(keys) == 1 && keys[0].Column == column {
+			if keys[0].Desc {
+				return []SortKey{}
+			}
+
+			return []SortKey{{Column: column, Desc: true}}
+		}
+
+		return []SortKey{{Column: column}}
+	}
+
+	result := []SortKey{}
+	found := false
+
+	for _, key := range keys {
+		if key.Column != column {
+			result = append(result, key)
+			continue
+		}
+
+		found = true
+
+		if !key.Desc {
+			result = append(result, SortKey{Column: column, Desc: true})
+		}
+	}
+
+	if !found {
+		result = append(result, SortKey{Column: column})
+	}
+
+	return result
+}
+
+// GetSortKeyIndex returns the priority (starting from 0) of column within
+// keys, or -1 if the config view isn't sorted by column.
+func GetSortKeyIndex(keys []SortKey, column string) int {
+	for i, key := range keys {
+		if key.Column == column {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	if a == b {
+		return 0
+	}
+
+	if b {
+		return -1
+	}
+
+	return 1
+}
+
+func compareTimes(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	}
+
+	if a.After(b) {
+		return 1
+	}
+
+	return 0
+}
+
+func compareDates(ay, am, ad, by, bm, bd int) int {
+	if ay != by {
+		return compareInts(ay, by)
+	}
+
+	if am != bm {
+		return compareInts(am, bm)
+	}
+
+	return compareInts(ad, bd)
+}
+
+// getFrequencySortRank orders frequencies from most to least frequent, with
+// unrecognized frequencies last.
+func getFrequencySortRank(f string) int {
+	rank := GetFrequencyRank(f)
+	if rank < 0 {
+		return len(constants.Frequencies)
+	}
+
+	return rank
+}
+
+// CompareTX compares two transactions by a single config column, returning
+// -1 if a sorts before b, 1 if it sorts after, and 0 if they're equal for the
+// purposes of that column. occurrences are the counts from Lint, which are
+// needed to compare by constants.ColumnOccurrences, and now is the reference
+// time for GetYearlyAmount.
+func CompareTX(a, b *TX, column string, occurrences map[string]int, now time.Time) int {
+	switch column {
+	case constants.ColumnOrder:
+		return compareInts(a.Order, b.Order)
+	case constants.ColumnAmount:
+		return compareInts(a.Amount, b.Amount)
+	case constants.ColumnAmounts:
+		return strings.Compare(GetAmountScheduleText(a), GetAmountScheduleText(b))
+	case constants.ColumnSeasonal:
+		_, aHi := a.GetSeasonalRange()
+		_, bHi := b.GetSeasonalRange()
+		return compareInts(aHi, bHi)
+	case constants.ColumnLink:
+		return strings.Compare(a.GetLinkSourceName(), b.GetLinkSourceName())
+	case constants.ColumnActive:
+		return compareBools(a.Active, b.Active)
+	case constants.ColumnName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case constants.ColumnFrequency:
+		return compareInts(getFrequencySortRank(a.Frequency), getFrequencySortRank(b.Frequency))
+	case constants.ColumnInterval:
+		return compareInts(a.Interval, b.Interval)
+	case constants.ColumnMonthlyRule:
+		return strings.Compare(a.MonthlyRule, b.MonthlyRule)
+	case constants.ColumnMonday:
+		return compareBools(a.Weekdays[constants.WeekdayMondayInt], b.Weekdays[constants.WeekdayMondayInt])
+	case constants.ColumnTuesday:
+		return compareBools(a.Weekdays[constants.WeekdayTuesdayInt], b.Weekdays[constants.WeekdayTuesdayInt])
+	case constants.ColumnWednesday:
+		return compareBools(a.Weekdays[constants.WeekdayWednesdayInt], b.Weekdays[constants.WeekdayWednesdayInt])
+	case constants.ColumnThursday:
+		return compareBools(a.Weekdays[constants.WeekdayThursdayInt], b.Weekdays[constants.WeekdayThursdayInt])
+	case constants.ColumnFriday:
+		return compareBools(a.Weekdays[constants.WeekdayFridayInt], b.Weekdays[constants.WeekdayFridayInt])
+	case constants.ColumnSaturday:
+		return compareBools(a.Weekdays[constants.WeekdaySaturdayInt], b.Weekdays[constants.WeekdaySaturdayInt])
+	case constants.ColumnSunday:
+		return compareBools(a.Weekdays[constants.WeekdaySundayInt], b.Weekdays[constants.WeekdaySundayInt])
+	case constants.ColumnStarts:
+		return compareDates(a.StartsYear, a.StartsMonth, a.StartsDay, b.StartsYear, b.StartsMonth, b.StartsDay)
+	case constants.ColumnEnds:
+		return compareDates(a.EndsYear, a.EndsMonth, a.EndsDay, b.EndsYear, b.EndsMonth, b.EndsDay)
+	case constants.ColumnRRule:
+		return strings.Compare(a.RRule, b.RRule)
+	case constants.ColumnShift:
+		return strings.Compare(a.Shift, b.Shift)
+	case constants.ColumnExceptions:
+		return compareInts(len(a.Exceptions), len(b.Exceptions))
+	case constants.ColumnNote:
+		return strings.Compare(strings.ToLower(a.Note), strings.ToLower(b.Note))
+	case constants.ColumnID:
+		return strings.Compare(strings.ToLower(a.ID), strings.ToLower(b.ID))
+	case constants.ColumnCreatedAt:
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case constants.ColumnUpdatedAt:
+		return compareTimes(a.UpdatedAt, b.UpdatedAt)
+	case constants.ColumnOccurrences:
+		return compareInts(occurrences[a.ID], occurrences[b.ID])
+	case constants.ColumnYearly, constants.ColumnMonthly:
+		return compareInts(GetYearlyAmount(a, now), GetYearlyAmount(b, now))
+	default:
+		return 0
+	}
+}
+
+// SortTX sorts the transactions by each of the provided keys in order of
+// priority. Transactions that are equal according to every key (or all
+// transactions, if no keys are provided) fall back to the manual order.
+func SortTX(txs []TX, keys []SortKey, occurrences map[string]int) {
+	now := time.Now()
+
+	sort.SliceStable(txs, func(i, j int) bool {
+		for _, key := range keys {
+			c := CompareTX(&txs[i], &txs[j], key.Column, occurrences, now)
+			if c == 0 {
+				continue
+			}
+
+			if key.Desc {
+				return c > 0
+			}
+
+			return c < 0
+		}
+
+		if txs[i].Order != txs[j].Order {
+			return txs[i].Order < txs[j].Order
+		}
+
+		return txs[i].CreatedAt.Before(txs[j].CreatedAt)
+	})
+}
@@ -0,0 +1,165 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// SweepRule moves money between the balance and another account whenever the
+// balance crosses a threshold, such as moving everything above $8,000 to
+// savings, or pulling from savings when the balance falls below $1,000. The
+// other account's balance isn't tracked.
+type SweepRule struct {
+	// Kind is constants.SweepAbove to move the excess above Threshold to
+	// Account, or constants.SweepBelow to top the balance back up to
+	// Threshold from Account.
+	Kind string `yaml:"kind"`
+	// Threshold is the balance that's swept down or topped up to, in cents.
+	Threshold int `yaml:"threshold"`
+	// Account is the name of the account that money is moved to or from,
+	// such as "Savings".
+	Account string `yaml:"account"`
+}
+
+// String formats the rule the way it's shown in the sweep rules editor, such
+// as "above $8000.00 to Savings".
+func (s SweepRule) String() string {
+	direction := constants.SweepTo
+	if s.Kind == constants.SweepBelow {
+		direction = constants.SweepFrom
+	}
+
+	return fmt.Sprintf("%v %v %v %v", s.Kind, lib.FormatAsCurrency(s.Threshold), direction, s.Account)
+}
+
+// apply returns how much the rule moves into (positive) or out of (negative)
+// a balance at the end of a day, which is 0 if the balance is within the
+// rule's threshold.
+func (s *SweepRule) apply(balance int) int {
+	switch {
+	case s.Kind == constants.SweepAbove && balance > s.Threshold:
+		return s.Threshold - balance
+	case s.Kind == constants.SweepBelow && balance < s.Threshold:
+		return s.Threshold - balance
+	}
+
+	return 0
+}
+
+// getName returns how a sweep of amount is listed in the day's transaction
+// names, such as "Sweep to Savings ($-2000.00)".
+func (s *SweepRule) getName(amount int) string {
+	format := constants.SweepToNameFormat
+	if s.Kind == constants.SweepBelow {
+		format = constants.SweepFromNameFormat
+	}
+
+	return fmt.Sprintf(format, s.Account, lib.FormatAsCurrency(amount))
+}
+
+// GetSweepRulesText formats the sweep rules with one per line.
+func GetSweepRulesText(rules []SweepRule) string {
+	lines := []string{}
+	for _, r := range rules {
+		lines = append(lines, r.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ParseSweepRules parses sweep rules as entered by the user, with one per
+// line, such as "above $8,000 to Savings" or "below $1,000 from Savings".
+// Blank lines and lines starting with "#" are ignored. A rule that tops up to
+// a balance at or above one that's swept down to is rejected, since the two
+// would keep undoing each other.
+func ParseSweepRules(s string) ([]SweepRule, error) {
+	rules := []SweepRule{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("line %v: \"%v\" is not a sweep rule; %v", n, line, constants.MsgSweepHint)
+		}
+
+		r := SweepRule{Kind: strings.ToLower(fields[0])}
+
+		direction := constants.SweepTo
+		switch r.Kind {
+		case constants.SweepAbove:
+		case constants.SweepBelow:
+			direction = constants.SweepFrom
+		default:
+			return nil, fmt.Errorf("line %v: a sweep rule starts with \"%v\" or \"%v\", not \"%v\"; %v", n, constants.SweepAbove, constants.SweepBelow, fields[0], constants.MsgSweepHint)
+		}
+
+		if !strings.ContainsAny(fields[1], "0123456789") {
+			return nil, fmt.Errorf("line %v: \"%v\" is not an amount; %v", n, fields[1], constants.MsgSweepHint)
+		}
+
+		r.Threshold = int(lib.ParseDollarAmount(strings.ReplaceAll(fields[1], ",", ""), true))
+
+		if !strings.EqualFold(fields[2], direction) {
+			return nil, fmt.Errorf("line %v: expected \"%v\" after the amount, not \"%v\"; %v", n, direction, fields[2], constants.MsgSweepHint)
+		}
+
+		// account names can have spaces
+		r.Account = strings.Join(fields[3:], " ")
+
+		rules = append(rules, r)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sweep rules: %v", err.Error())
+	}
+
+	lowestAbove := math.MaxInt
+	for _, r := range rules {
+		if r.Kind == constants.SweepAbove && r.Threshold < lowestAbove {
+			lowestAbove = r.Threshold
+		}
+	}
+
+	for _, r := range rules {
+		if r.Kind == constants.SweepBelow && r.Threshold >= lowestAbove {
+			return nil, fmt.Errorf("\"%v\" tops up to %v, which isn't below the %v that's swept down to, so the rules would undo each other", r.String(), lib.FormatAsCurrency(r.Threshold), lib.FormatAsCurrency(lowestAbove))
+		}
+	}
+
+	return rules, nil
+}
+
+// applySweeps evaluates the sweep rules in order against the balance at the
+// end of a result's day, and adds each sweep to the day's income or expenses
+// and its transaction names. It returns the balance after the sweeps.
+func applySweeps(rules []SweepRule, r *lib.Result, balance int) int {
+	for i := range rules {
+		amount := rules[i].apply(balance)
+		if amount == 0 {
+			continue
+		}
+
+		if amount > 0 {
+			r.DayIncome += amount
+		} else {
+			r.DayExpenses += amount
+		}
+
+		r.DayNet += amount
+		r.DayTransactionNamesSlice = append(r.DayTransactionNamesSlice, rules[i].getName(amount))
+		balance += amount
+	}
+
+	return balance
+}
@@ -0,0 +1,108 @@
+package planner
+
+import (
+	"errors"
+	"time"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// TX is a recurring transaction definition. It embeds the finance-planner-lib
+// TX so that its fields (and its JSON/YAML representation) stay compatible
+// with existing config files, while allowing this application to store a few
+// additional fields of its own alongside each transaction.
+type TX struct {
+	lib.TX `yaml:",inline"`
+
+	// Order is the manual ordering of this transaction in the config view,
+	// starting from 1. A value of 0 means that no order has been assigned yet.
+	Order int `yaml:"order"`
+
+	// MonthlyRule optionally changes which day of the month a MONTHLY
+	// transaction recurs on, such as "last business day"; see MonthlyRule.
+	// When empty, it recurs on the same day of the month as its start date.
+	MonthlyRule string `yaml:"monthlyRule,omitempty"`
+
+	// Shift is what happens when the transaction falls on a weekend or
+	// holiday: it's moved to the previous (constants.ShiftPrevious) or next
+	// (constants.ShiftNext) business day. When empty, it isn't moved.
+	Shift string `yaml:"shift,omitempty"`
+
+	// Exceptions change individual occurrences, such as skipping one or
+	// changing its amount; see Exception. They're sorted by date.
+	Exceptions []Exception `yaml:"exceptions,omitempty"`
+
+	// SeasonalAmounts optionally replace the amount with one per month, from
+	// January to December, such as for a utility bill. It's either empty or
+	// has twelve values.
+	SeasonalAmounts []int `yaml:"seasonalAmounts,omitempty"`
+
+	// AmountChanges replace the amount from a date onwards, such as a rent
+	// increase; see AmountChange. They're sorted by date.
+	AmountChanges []AmountChange `yaml:"amountChanges,omitempty"`
+
+	// Escalation grows the amount every few months, such as a yearly raise;
+	// see Escalation.
+	Escalation *Escalation `yaml:"escalation,omitempty"`
+
+	// Link optionally defines the amount as a percentage of another
+	// transaction's amount; see AmountLink. It replaces the rest of the
+	// amount fields.
+	Link *AmountLink `yaml:"link,omitempty"`
+
+	// Formula optionally calculates the amount from the plan's variables,
+	// such as "salary*0.1" (see EvalFormula). It's saved without its leading
+	// "=", and Amount is kept up to date with it by ApplyFormulas.
+	Formula string `yaml:"formula,omitempty"`
+
+	// Loan is the loan that the transaction is the monthly payment of, if it
+	// was generated from one; see ApplyLoan.
+	Loan *Loan `yaml:"loan,omitempty"`
+
+	// SinkingFund is the ID of the bill that the transaction moves money into
+	// (if it's an expense) or out of (if it's income) the sinking fund of, if
+	// it was generated by the sinking fund calculator; see GetSinkingFunds.
+	SinkingFund string `yaml:"sinkingFund,omitempty"`
+
+	// linkSource is a copy of the transaction that Link refers to, which is
+	// looked up by LinkAmounts.
+	linkSource *TX
+}
+
+// GetNewTX returns an empty transaction with sensible defaults based on the
+// provided time t (which is typically time.Now()), placed at the end of the
+// manual ordering of txs.
+func GetNewTX(t time.Time, txs []TX) TX {
+	return TX{
+		TX:    lib.GetNewTX(t),
+		Order: GetNextOrder(txs),
+	}
+}
+
+// RemoveTXByID manipulates an input TX slice by removing a TX with the provided
+// id.
+func RemoveTXByID(txs *[]TX, id string) {
+	for i := range *txs {
+		if (*txs)[i].ID != id {
+			continue
+		}
+
+		*txs = append((*txs)[:i], (*txs)[i+1:]...)
+
+		break
+	}
+}
+
+// GetTXByID finds the index of a TX for the provided id, returning an error
+// and -1 if not present.
+func GetTXByID(txs *[]TX, id string) (int, error) {
+	for i := range *txs {
+		if (*txs)[i].ID != id {
+			continue
+		}
+
+		return i, nil
+	}
+
+	return -1, errors.New("not present")
+}
@@ -0,0 +1,276 @@
+package planner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// Problem is something wrong with a transaction definition, such as an end
+// date that comes before its start date. Columns are the config columns (such
+// as constants.ColumnEnds) that the user would need to change to fix it.
+type Problem struct {
+	TXID    string
+	Columns []string
+	Message string
+}
+
+// Problems are all of the problems found in a set of transactions.
+type Problems []Problem
+
+// ByCell indexes the problems by TX ID and then by column, so that the
+// problems for a single cell can be looked up quickly.
+func (p Problems) ByCell() map[string]map[string][]string {
+	result := make(map[string]map[string][]string)
+
+	for _, problem := range p {
+		if result[problem.TXID] == nil {
+			result[problem.TXID] = make(map[string][]string)
+		}
+
+		for _, column := range problem.Columns {
+			result[problem.TXID][column] = append(result[problem.TXID][column], problem.Message)
+		}
+	}
+
+	return result
+}
+
+// InputProblems are edits that were rejected because they couldn't be parsed,
+// keyed by TX ID and then by column. They're kept until the field is
+// successfully edited, so that the user can see why their edit didn't stick.
+type InputProblems map[string]map[string]string
+
+// Set records a rejected edit for a single field.
+func (ip InputProblems) Set(id, column, message string) {
+	if ip[id] == nil {
+		ip[id] = make(map[string]string)
+	}
+
+	ip[id][column] = message
+}
+
+// Clear forgets the rejected edit for a single field, if there was one.
+func (ip InputProblems) Clear(id, column string) {
+	delete(ip[id], column)
+
+	if len(ip[id]) == 0 {
+		delete(ip, id)
+	}
+}
+
+// ParseDate parses a YYYY-MM-DD date as entered by the user. An empty value
+// (or 0-0-0) is allowed, and means that the date is unset.
+func ParseDate(s string) (year, month, day int, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, 0, nil
+	}
+
+	vals := strings.Split(s, "-")
+	if len(vals) != 3 {
+		return 0, 0, 0, fmt.Errorf("\"%v\" is not a date in the format YYYY-MM-DD", s)
+	}
+
+	nums := [3]int{}
+	for i, v := range vals {
+		nums[i], err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("\"%v\" is not a date in the format YYYY-MM-DD", s)
+		}
+	}
+
+	year, month, day = nums[0], nums[1], nums[2]
+	if year == 0 && month == 0 && day == 0 {
+		return 0, 0, 0, nil
+	}
+
+	if !IsValidDate(year, month, day) {
+		return 0, 0, 0, fmt.Errorf("%v is not a valid date", s)
+	}
+
+	return year, month, day, nil
+}
+
+// IsValidDate returns true if the date exists on the calendar, e.g. it returns
+// false for 2023-02-29.
+func IsValidDate(year, month, day int) bool {
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+
+	return t.Year() == year && int(t.Month()) == month && t.Day() == day
+}
+
+// ParseInterval parses the Interval column as entered by the user, which must
+// be a positive whole number.
+func ParseInterval(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("\"%v\" is not a valid interval; enter a whole number of 1 or more", strings.TrimSpace(s))
+	}
+
+	return n, nil
+}
+
+func isSetDate(year, month, day int) bool {
+	return year != 0 || month != 0 || day != 0
+}
+
+// ValidateTX checks a single transaction for problems that would make it
+// behave differently than the user most likely intended.
+func ValidateTX(tx *TX) Problems {
+	problems := Problems{}
+
+	add := func(message string, columns ...string) {
+		problems = append(problems, Problem{TXID: tx.ID, Columns: columns, Message: message})
+	}
+
+	if GetFrequencyRank(tx.Frequency) < 0 {
+		add(fmt.Sprintf("\"%v\" is not a recognized frequency.", tx.Frequency), constants.ColumnFrequency)
+	}
+
+	if tx.Interval <= 0 {
+		add("The interval must be 1 or more.", constants.ColumnInterval)
+	}
+
+	startsSet := isSetDate(tx.StartsYear, tx.StartsMonth, tx.StartsDay)
+	endsSet := isSetDate(tx.EndsYear, tx.EndsMonth, tx.EndsDay)
+	startsValid := !startsSet || IsValidDate(tx.StartsYear, tx.StartsMonth, tx.StartsDay)
+	endsValid := !endsSet || IsValidDate(tx.EndsYear, tx.EndsMonth, tx.EndsDay)
+
+	if !startsValid {
+		add(fmt.Sprintf("%v is not a valid date.", tx.GetStartDateString()), constants.ColumnStarts)
+	}
+
+	if !endsValid {
+		add(fmt.Sprintf("%v is not a valid date.", tx.GetEndsDateString()), constants.ColumnEnds)
+	}
+
+	if startsSet && endsSet && startsValid && endsValid && tx.GetEndsDate().Before(tx.GetStartsDate()) {
+		add("The end date is before the start date, so this never occurs.", constants.ColumnStarts, constants.ColumnEnds)
+	}
+
+	if shift, err := ParseShift(tx.Shift); err != nil || shift != tx.Shift {
+		add(fmt.Sprintf("\"%v\" is not a recognized weekend/holiday policy.", tx.Shift), constants.ColumnShift)
+	}
+
+	for _, e := range tx.Exceptions {
+		if e.GetDate().IsZero() || (e.MoveTo != "" && e.GetMoveTo().IsZero()) {
+			add(fmt.Sprintf("The exception \"%v\" has an invalid date.", e.String()), constants.ColumnExceptions)
+		}
+	}
+
+	if len(tx.SeasonalAmounts) > 0 && !tx.HasSeasonalAmounts() {
+		add(fmt.Sprintf("The seasonal amount table has %v amounts instead of %v, so the fixed amount is used.", len(tx.SeasonalAmounts), monthsPerYear), constants.ColumnSeasonal)
+	}
+
+	for _, c := range tx.AmountChanges {
+		if c.GetFrom().IsZero() {
+			add(fmt.Sprintf("The amount change \"%v\" has an invalid date.", c.String()), constants.ColumnAmounts)
+		}
+	}
+
+	if e := tx.Escalation; e != nil {
+		if e.GetFrom().IsZero() || e.Months <= 0 {
+			add(fmt.Sprintf("The escalation rule \"%v\" needs a valid date and a number of months.", e.String()), constants.ColumnAmounts)
+		}
+
+		if len(tx.AmountChanges) > 0 {
+			add("Both amount changes and an escalation rule are set; the escalation is applied on top of the amount changes.", constants.ColumnAmounts)
+		}
+	}
+
+	if tx.RRule != "" {
+		if _, err := ParseRRule(tx.RRule, time.Now()); err != nil {
+			add(err.Error(), constants.ColumnRRule)
+		}
+
+		// the rule overrides the rest of the recurrence columns, so there's
+		// nothing more to check
+		return problems
+	}
+
+	hasWeekdays := false
+	for i := range constants.Weekdays {
+		if tx.Weekdays[i] {
+			hasWeekdays = true
+		}
+	}
+
+	switch tx.Frequency {
+	case constants.WEEKLY:
+		if !hasWeekdays {
+			add(
+				"This is weekly, but no weekdays are checked, so it occurs every day.",
+				append([]string{constants.ColumnFrequency}, constants.Weekdays...)...,
+			)
+		}
+	case constants.MONTHLY:
+		if startsValid && tx.StartsDay > 28 && tx.MonthlyRule == "" {
+			add(fmt.Sprintf(
+				"This is monthly starting on day %v, so it's skipped in months that have fewer days.",
+				tx.StartsDay,
+			), constants.ColumnStarts)
+		}
+	case constants.YEARLY:
+		if startsValid && tx.StartsMonth == 2 && tx.StartsDay == 29 {
+			add("This is yearly starting on February 29, so it only occurs in leap years.", constants.ColumnStarts)
+		}
+	}
+
+	if tx.MonthlyRule != "" {
+		if _, err := ParseMonthlyRule(tx.MonthlyRule); err != nil {
+			add(err.Error(), constants.ColumnMonthlyRule)
+		} else if tx.Frequency != constants.MONTHLY {
+			add(
+				fmt.Sprintf("The monthly rule is ignored for %v transactions.", strings.ToLower(tx.Frequency)),
+				constants.ColumnFrequency,
+				constants.ColumnMonthlyRule,
+			)
+		}
+	}
+
+	if hasWeekdays && tx.Frequency != constants.WEEKLY && GetFrequencyRank(tx.Frequency) >= 0 {
+		message := fmt.Sprintf("Weekdays are ignored for %v transactions.", strings.ToLower(tx.Frequency))
+		if tx.Frequency == constants.DAILY {
+			message = "Weekdays are ignored for daily transactions, which occur every Interval days; use weekly to pick the weekdays."
+		}
+
+		add(message, append([]string{constants.ColumnFrequency}, constants.Weekdays...)...)
+	}
+
+	return problems
+}
+
+// Validate checks every transaction for problems, and includes any edits
+// that were rejected for the transactions that still exist.
+func Validate(txs []TX, input InputProblems) Problems {
+	problems := Problems{}
+
+	for i := range txs {
+		for _, column := range constants.ConfigColumns {
+			message, ok := input[txs[i].ID][column]
+			if !ok {
+				continue
+			}
+
+			problems = append(problems, Problem{
+				TXID:    txs[i].ID,
+				Columns: []string{column},
+				Message: message,
+			})
+		}
+
+		problems = append(problems, ValidateTX(&txs[i])...)
+	}
+
+	problems = append(problems, getLinkProblems(txs)...)
+
+	return problems
+}
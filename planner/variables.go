@@ -0,0 +1,183 @@
+package planner
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+)
+
+// Variable is a named number for a plan, such as "salary = 5200", which
+// amounts can refer to in a formula (see TX.Formula).
+type Variable struct {
+	Name  string  `yaml:"name"`
+	Value float64 `yaml:"value"`
+}
+
+// String formats the variable the way it's shown in the variables editor,
+// such as "salary = 5200".
+func (v Variable) String() string {
+	return fmt.Sprintf("%v = %v", v.Name, strconv.FormatFloat(v.Value, 'f', -1, 64))
+}
+
+// GetVariableValues indexes the variables by their names in lower case, since
+// they're looked up case-insensitively.
+func GetVariableValues(vars []Variable) map[string]float64 {
+	values := make(map[string]float64)
+	for _, v := range vars {
+		values[strings.ToLower(v.Name)] = v.Value
+	}
+
+	return values
+}
+
+// GetVariablesText formats the variables with one per line.
+func GetVariablesText(vars []Variable) string {
+	lines := []string{}
+	for _, v := range vars {
+		lines = append(lines, v.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ParseVariables parses variables as entered by the user, with one per line,
+// such as "salary = 5200". A value can also be arithmetic, such as "62400/12"
+// or "salary/2" (which can use the variables on the lines above it), in which
+// case its result is saved. Names can only have ASCII letters, digits and
+// underscores, so that formulas can refer to them. Blank lines and lines
+// starting with "#" are ignored.
+func ParseVariables(s string) ([]Variable, error) {
+	vars := []Variable{}
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(strings.NewReader(s))
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, expr, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %v: \"%v\" is not a variable; %v", n, line, constants.MsgVariablesHint)
+		}
+
+		for i, r := range name {
+			if !isVariableNameChar(r) || (i == 0 && isDigit(r)) {
+				return nil, fmt.Errorf("line %v: \"%v\" is not a valid variable name, since names can only have the letters A-Z, digits and underscores, and can't start with a digit", n, name)
+			}
+		}
+
+		if _, exists := values[strings.ToLower(name)]; exists {
+			return nil, fmt.Errorf("line %v: the variable \"%v\" is defined more than once", n, name)
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(expr), constants.FormulaPrefix) {
+			return nil, fmt.Errorf("line %v: a variable's value doesn't start with \"=\"; write e.g. \"%v = salary/12\" instead", n, name)
+		}
+
+		value, err := EvalExpression(expr, values)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", n, err.Error())
+		}
+
+		values[strings.ToLower(name)] = value
+		vars = append(vars, Variable{Name: name, Value: value})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read variables: %v", err.Error())
+	}
+
+	return vars, nil
+}
+
+// IsFormula returns true if an amount as entered by the user is a formula,
+// i.e. it starts with "=".
+func IsFormula(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), constants.FormulaPrefix)
+}
+
+// NormalizeFormula returns a formula without its leading "=" and surrounding
+// spaces, which is how it's saved.
+func NormalizeFormula(s string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), constants.FormulaPrefix))
+}
+
+// EvalFormula calculates the amount (in cents) of a formula such as
+// "salary*0.1" or "1200/12" (without its leading "="). As with the Amount
+// column, the result is an expense unless the formula starts with a "+", e.g.
+// "+salary". A formula that starts with a "+" keeps the sign that it
+// evaluates to, so "+salary-tax" is an expense if the tax is larger than the
+// salary.
+func EvalFormula(formula string, values map[string]float64) (int, error) {
+	v, err := EvalExpression(formula, values)
+	if err != nil {
+		return 0, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(formula), "+") {
+		return int(math.Round(v * 100)), nil
+	}
+
+	return -int(math.Round(math.Abs(v) * 100)), nil
+}
+
+// GetAmountInputText returns the transaction's amount the way it's edited,
+// which is its formula (e.g. "=salary*0.1") if it has one, and otherwise the
+// same as GetAmountText.
+func GetAmountInputText(tx *TX) string {
+	if tx.Formula != "" {
+		return constants.FormulaPrefix + tx.Formula
+	}
+
+	return GetAmountText(tx)
+}
+
+// ApplyFormulas recalculates the amount of every transaction that has a
+// formula, using the provided variables. Transactions whose formula can't be
+// calculated (e.g. because a variable was removed) keep their last amount;
+// see GetFormulaProblems.
+func ApplyFormulas(txs []TX, vars []Variable) {
+	values := GetVariableValues(vars)
+
+	for i := range txs {
+		if txs[i].Formula == "" {
+			continue
+		}
+
+		amount, err := EvalFormula(txs[i].Formula, values)
+		if err == nil {
+			txs[i].Amount = amount
+		}
+	}
+}
+
+// GetFormulaProblems reports the formulas that can't be calculated with the
+// provided variables.
+func GetFormulaProblems(txs []TX, vars []Variable) Problems {
+	problems := Problems{}
+	values := GetVariableValues(vars)
+
+	for i := range txs {
+		if txs[i].Formula == "" {
+			continue
+		}
+
+		if _, err := EvalFormula(txs[i].Formula, values); err != nil {
+			problems = append(problems, Problem{
+				TXID:    txs[i].ID,
+				Columns: []string{constants.ColumnAmount},
+				Message: fmt.Sprintf("The formula \"=%v\" can't be calculated, so its last amount is used: %v", txs[i].Formula, err.Error()),
+			})
+		}
+	}
+
+	return problems
+}
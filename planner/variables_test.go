@@ -0,0 +1,95 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVariables(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Variable
+		err  string
+	}{
+		{
+			name: "numbers and arithmetic",
+			text: "salary = 5200\n# comment\n\nyearly = 62400/12",
+			want: []Variable{{Name: "salary", Value: 5200}, {Name: "yearly", Value: 5200}},
+		},
+		{
+			name: "references to earlier variables",
+			text: "salary = 5200\nhalf = Salary/2",
+			want: []Variable{{Name: "salary", Value: 5200}, {Name: "half", Value: 2600}},
+		},
+		{name: "references to later variables", text: "half = salary/2\nsalary = 5200", err: "no variable named"},
+		{name: "non-ASCII name", text: "café = 5", err: "not a valid variable name"},
+		{name: "leading digit", text: "1st = 5", err: "not a valid variable name"},
+		{name: "leading equals sign", text: "half = =5200/2", err: "doesn't start with"},
+		{name: "duplicate", text: "a = 1\nA = 2", err: "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVariables(tt.text)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseVariables returned an error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvalFormula(t *testing.T) {
+	values := map[string]float64{"salary": 5200, "tax": 6000}
+
+	tests := []struct {
+		formula string
+		want    int
+	}{
+		{"salary*0.1", -52000},
+		{"+salary*0.1", 52000},
+		{"+salary-tax", -80000},
+		{"salary-tax", -80000},
+		{"1200/12", -10000},
+		{"$1,850.00/2", -92500},
+		{"+(salary+tax)/2", 560000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			got, err := EvalFormula(tt.formula, values)
+			if err != nil {
+				t.Fatalf("EvalFormula returned an error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionRejectsNonASCIINames(t *testing.T) {
+	_, err := EvalExpression("café*2", map[string]float64{"caf": 1})
+	if err == nil || !strings.Contains(err.Error(), "unexpected \"é") {
+		t.Errorf("got error %v, want one about an unexpected \"é\"", err)
+	}
+}
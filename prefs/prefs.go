@@ -0,0 +1,108 @@
+package prefs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// ColumnPrefs describes how the user has arranged the columns of a tree view.
+// Columns are referred to by their (untranslated) column names, such as
+// constants.ColumnAmount.
+type ColumnPrefs struct {
+	// Hidden contains the columns that the user has chosen to hide.
+	Hidden map[string]bool
+	// Order is the order that the columns were last arranged in. Columns that
+	// are not present (such as ones added in a newer version) keep their
+	// default position.
+	Order []string
+	// Widths are the widths that the user has resized columns to.
+	Widths map[string]int
+}
+
+// Prefs are application-wide preferences that apply to every window, as
+// opposed to the per-file settings that are stored in a config file.
+type Prefs struct {
+	ConfigColumns  ColumnPrefs
+	ResultsColumns ColumnPrefs
+	// ShowDetailPanel is whether the transaction detail panel is expanded.
+	ShowDetailPanel bool
+}
+
+// New returns an empty set of preferences with every map initialized.
+func New() *Prefs {
+	p := &Prefs{}
+	p.init()
+
+	return p
+}
+
+func (p *Prefs) init() {
+	for _, cp := range []*ColumnPrefs{&p.ConfigColumns, &p.ResultsColumns} {
+		if cp.Hidden == nil {
+			cp.Hidden = make(map[string]bool)
+		}
+
+		if cp.Widths == nil {
+			cp.Widths = make(map[string]int)
+		}
+	}
+}
+
+// Load reads the preferences from the provided file. If the file doesn't exist
+// yet, empty preferences are returned without an error.
+func Load(file string) (*Prefs, error) {
+	p := New()
+
+	if file == "" {
+		return p, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+
+	if err != nil {
+		return p, fmt.Errorf("failed to read prefs json: %v", err.Error())
+	}
+
+	err = json.Unmarshal(b, p)
+	if err != nil {
+		return New(), fmt.Errorf("failed to unmarshal prefs json: %v", err.Error())
+	}
+
+	p.init()
+
+	return p, nil
+}
+
+// Save writes the preferences to the provided file, creating its parent
+// directory if needed.
+func Save(file string, p *Prefs) error {
+	if file == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal prefs json: %v", err.Error())
+	}
+
+	dir := path.Dir(file)
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create parent directory \"%v\" for saving prefs json: %v", dir, err.Error())
+	}
+
+	err = os.WriteFile(file, b, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write prefs json to file %v: %v", file, err.Error())
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package state
 
 import (
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/prefs"
+
 	lib "github.com/charles-m-knox/finance-planner-lib"
 
 	"github.com/gotk3/gotk3/gtk"
@@ -13,7 +16,7 @@ import (
 // to be shown from anywhere, should be stored here as pointers.
 type WinState struct {
 	HideInactive         bool
-	ConfigColumnSort     string
+	ConfigSort           []planner.SortKey // empty when using the manual order
 	OpenFileName         string
 	StartingBalance      int
 	StartDate            string
@@ -22,7 +25,7 @@ type WinState struct {
 	ShowMessageDialog    *func(m string, t gtk.MessageType)
 	ConfigListStore      *gtk.ListStore
 	ResultsListStore     *gtk.ListStore
-	TX                   *[]lib.TX // transaction definitions for the current window
+	TX                   *[]planner.TX // transaction definitions for the current window
 	Results              *[]lib.Result
 	App                  *gtk.Application
 	Win                  *gtk.ApplicationWindow
@@ -30,6 +33,32 @@ type WinState struct {
 	Notebook             *gtk.Notebook
 	ConfigScrolledWindow *gtk.ScrolledWindow
 	ConfigTreeView       *gtk.TreeView
-	ConfigVScroll        float64 // for recalling where to scroll when clearing liststore
-	ConfigHScroll        float64 // for recalling where to scroll when clearing liststore
+	ConfigColumns        map[int]*gtk.TreeViewColumn // keyed by COLUMN_ constants
+	ResultsTreeView      *gtk.TreeView
+	Prefs                *prefs.Prefs // application-wide, loaded when the window is created
+	PrefsFileName        string
+	PrefsSavePending     bool
+	ConfigVScroll        float64       // for recalling where to scroll when clearing liststore
+	ConfigHScroll        float64       // for recalling where to scroll when clearing liststore
+	DetailRevealer       *gtk.Revealer // collapsible transaction detail panel
+	RefreshDetail        func()        // repopulates the detail panel, set once it's built
+	Problems             planner.Problems
+	ProblemCells         map[string]map[string][]string // Problems indexed by TX ID, then column
+	InputProblems        planner.InputProblems          // edits that were rejected
+	ProblemsLabel        *gtk.Label                     // "N problems" indicator in the header bar
+	Occurrences          map[string]int                 // times each TX occurs from StartDate to EndDate, keyed by ID
+	TotalsLabel          *gtk.Label                     // recurring totals footer in the config tab
+	HolidaysFile         string                         // holiday calendar file, empty for the US federal holidays
+	Holidays             planner.Holidays               // loaded from HolidaysFile, nil for the US federal holidays
+	Variables            []planner.Variable             // named numbers that amount formulas refer to
+	Sweeps               []planner.SweepRule            // rules that move money when the balance crosses a threshold
+	Interest             *planner.Interest              // how interest accrues on the balance, nil if it doesn't
+	Goals                []planner.Goal                 // savings goals, marked on the results' balances
+	SinkingFunds         []string                       // each results day's sinking fund balances
+	Debts                []planner.Debt                 // last entered in the debt payoff planner, not saved
+	DebtBudget           int                            // last entered in the debt payoff planner, not saved
+	PayPeriodIncome      string                         // ID of the paycheck last chosen in the pay periods view, not saved
+	MinimumBalance       int                            // balance that the safe-to-spend amount stays above
+	MinimumBalanceInput  *gtk.Entry                     // minimum balance input on the results tab
+	SafeToSpendLabel     *gtk.Label                     // safe-to-spend indicator in the header bar
 }
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// getSelectedTX returns copies of the selected transactions, in the order they
+// are currently displayed in the config view.
+func getSelectedTX(ws *state.WinState) []planner.TX {
+	txs := []planner.TX{}
+
+	for _, id := range GetConfigListStoreIDs(ws) {
+		if !ws.SelectedConfIDs[id] {
+			continue
+		}
+
+		i, err := planner.GetTXByID(ws.TX, id)
+		if err != nil {
+			log.Printf("config item with id=%v was missing", id)
+			continue
+		}
+
+		txs = append(txs, (*ws.TX)[i])
+	}
+
+	return txs
+}
+
+// CopyConfItems places the selected transactions on the clipboard. By default
+// they are copied as JSON, which can be pasted into any window of this
+// application without losing anything. When asTSV is true, they are copied as
+// tab-separated values with a header row instead, which spreadsheets
+// understand.
+func CopyConfItems(ws *state.WinState, asTSV bool) {
+	txs := getSelectedTX(ws)
+	if len(txs) == 0 {
+		(*ws.ShowMessageDialog)(constants.MsgNothingSelectedToCopy, gtk.MESSAGE_INFO)
+		return
+	}
+
+	var text string
+
+	if asTSV {
+		text = planner.GetTXAsTSV(txs)
+	} else {
+		var err error
+
+		text, err = planner.GetTXAsJSON(txs)
+		if err != nil {
+			(*ws.ShowMessageDialog)(fmt.Sprintf("failed to copy transactions: %v", err.Error()), gtk.MESSAGE_ERROR)
+			return
+		}
+	}
+
+	clipboard, err := gtk.ClipboardGet(gdk.SELECTION_CLIPBOARD)
+	if err != nil {
+		log.Printf("failed to get clipboard: %v", err.Error())
+		return
+	}
+
+	clipboard.SetText(text)
+
+	// allows the copied transactions to be pasted even after this
+	// application has exited, if a clipboard manager is running
+	clipboard.Store()
+
+	log.Printf("copied %v transactions to the clipboard", len(txs))
+}
+
+// PasteConfItems inserts the transactions from the clipboard, which can be
+// either JSON copied from any window of this application, or tab-separated
+// values copied from a spreadsheet with a header row. Pasted transactions are
+// given new IDs and timestamps, just like cloned ones, and are selected after
+// being inserted.
+func PasteConfItems(ws *state.WinState) {
+	clipboard, err := gtk.ClipboardGet(gdk.SELECTION_CLIPBOARD)
+	if err != nil {
+		log.Printf("failed to get clipboard: %v", err.Error())
+		return
+	}
+
+	text, err := clipboard.WaitForText()
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf("failed to read clipboard: %v", err.Error()), gtk.MESSAGE_ERROR)
+		return
+	}
+
+	txs, err := planner.ParseClipboardTX(text, time.Now(), *ws.TX)
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf("failed to paste transactions: %v", err.Error()), gtk.MESSAGE_ERROR)
+		return
+	}
+
+	pasted := make(map[string]bool, len(txs))
+	for i := range txs {
+		pasted[txs[i].ID] = true
+	}
+
+	*ws.TX = append(*ws.TX, txs...)
+
+	// pasted items are added at the end of the manual order
+	SetConfigScrollPosition(ws, 65535, -1)
+
+	err = SyncConfigListStore(ws)
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf(
+			"Error code %v - failed to sync after pasting: %v",
+			constants.ErrorCodeSyncConfigListStoreAfterPaste,
+			err.Error(),
+		), gtk.MESSAGE_ERROR)
+	}
+
+	SelectConfigRowsByID(ws, pasted)
+	RestoreConfigScrollPosition(ws)
+
+	UpdateResults(ws, false)
+
+	log.Printf("pasted %v transactions from the clipboard", len(txs))
+}
+
+// ConfigKeyPressHandler handles ctrl+c and ctrl+v while the config tree view
+// has focus. These aren't registered as window-wide accelerators, since that
+// would prevent copying & pasting text in cell editors and other inputs.
+func ConfigKeyPressHandler(ws *state.WinState) func(*gtk.TreeView, *gdk.Event) bool {
+	return func(_ *gtk.TreeView, ev *gdk.Event) bool {
+		k := gdk.EventKeyNewFromEvent(ev)
+
+		mods := gdk.ModifierType(k.State()) & gtk.AcceleratorGetDefaultModMask()
+		if mods != gdk.CONTROL_MASK {
+			return false
+		}
+
+		switch k.KeyVal() {
+		case gdk.KEY_c, gdk.KEY_C:
+			CopyConfItems(ws, false)
+			return true
+		case gdk.KEY_v, gdk.KEY_V:
+			PasteConfItems(ws)
+			return true
+		}
+
+		return false
+	}
+}
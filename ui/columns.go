@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/prefs"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// SavePrefs writes the application-wide preferences to disk. Since some
+// preferences (such as column widths) change many times per second while the
+// user is dragging things around, the actual write is delayed slightly so that
+// a burst of changes only results in a single write.
+func SavePrefs(ws *state.WinState) {
+	if ws.Prefs == nil || ws.PrefsSavePending {
+		return
+	}
+
+	ws.PrefsSavePending = true
+
+	glib.TimeoutAdd(constants.PrefsSaveDelayMs, func() bool {
+		ws.PrefsSavePending = false
+
+		err := prefs.Save(ws.PrefsFileName, ws.Prefs)
+		if err != nil {
+			log.Printf("failed to save preferences: %v", err.Error())
+		}
+
+		return false
+	})
+}
+
+// SetupColumnPrefs applies the user's column preferences (visibility, order
+// and widths) to a tree view, and keeps those preferences updated as the user
+// changes them. names must contain the name of every column in the tree view,
+// in the order they were appended. Right-clicking any column header shows a
+// menu that allows each column to be shown or hidden.
+func SetupColumnPrefs(ws *state.WinState, tv *gtk.TreeView, names []string, cp *prefs.ColumnPrefs) {
+	byName := make(map[string]*gtk.TreeViewColumn)
+	byNative := make(map[uintptr]string)
+
+	for i, name := range names {
+		col := tv.GetColumn(i)
+		if col == nil {
+			log.Printf("column %v (%v) is missing from tree view", i, name)
+			continue
+		}
+
+		byName[name] = col
+		byNative[col.Native()] = name
+
+		col.SetReorderable(true)
+		col.SetVisible(!cp.Hidden[name])
+
+		if w, ok := cp.Widths[name]; ok && w > 0 {
+			col.SetFixedWidth(w)
+		}
+	}
+
+	var prev *gtk.TreeViewColumn
+
+	for _, name := range cp.Order {
+		col, ok := byName[name]
+		if !ok {
+			continue
+		}
+
+		tv.MoveColumnAfter(col, prev)
+		prev = col
+	}
+
+	// the menu is kept here so that it isn't garbage collected while shown
+	var menu *gtk.Menu
+
+	showMenu := func(ev *gdk.Event) {
+		var err error
+
+		menu, err = gtk.MenuNew()
+		if err != nil {
+			log.Printf("failed to create columns menu: %v", err.Error())
+			return
+		}
+
+		for _, name := range names {
+			col, ok := byName[name]
+			if !ok {
+				continue
+			}
+
+			item, err := gtk.CheckMenuItemNewWithLabel(name)
+			if err != nil {
+				log.Printf("failed to create columns menu item %v: %v", name, err.Error())
+				continue
+			}
+
+			item.SetActive(col.GetVisible())
+			item.Connect(constants.GtkSignalToggled, func(i *gtk.CheckMenuItem) {
+				visible := i.GetActive()
+				if visible == col.GetVisible() {
+					return
+				}
+
+				// never allow the last visible column to be hidden, since
+				// there'd be no header left to bring the others back
+				if !visible && countVisibleColumns(byName) <= 1 {
+					i.SetActive(true)
+					return
+				}
+
+				col.SetVisible(visible)
+
+				if visible {
+					delete(cp.Hidden, name)
+				} else {
+					cp.Hidden[name] = true
+				}
+
+				SavePrefs(ws)
+			})
+
+			menu.Append(item)
+		}
+
+		menu.ShowAll()
+		menu.PopupAtPointer(ev)
+	}
+
+	for name, col := range byName {
+		btn, err := col.GetButton()
+		if err != nil {
+			log.Printf("failed to get %v column header button: %v", name, err.Error())
+			continue
+		}
+
+		btn.ToWidget().Connect(constants.GtkSignalButtonPressEvent, func(_ *gtk.Button, ev *gdk.Event) bool {
+			if gdk.EventButtonNewFromEvent(ev).Button() != gdk.BUTTON_SECONDARY {
+				return false
+			}
+
+			showMenu(ev)
+
+			return true
+		})
+
+		// the fixed width is set by gtk whenever the user resizes a column
+		col.Connect(constants.GtkSignalNotifyFixedWidth, func(c *gtk.TreeViewColumn) {
+			w := c.GetFixedWidth()
+			if w <= 0 || cp.Widths[name] == w {
+				return
+			}
+
+			cp.Widths[name] = w
+			SavePrefs(ws)
+		})
+	}
+
+	tv.Connect(constants.GtkSignalColumnsChanged, func(t *gtk.TreeView) {
+		order := []string{}
+
+		cols := t.GetColumns()
+		for l := cols; l != nil; l = l.Next() {
+			col, ok := l.Data().(*gtk.TreeViewColumn)
+			if !ok {
+				continue
+			}
+
+			if name, ok := byNative[col.Native()]; ok {
+				order = append(order, name)
+			}
+		}
+
+		// columns are removed one at a time when the tree view is destroyed,
+		// which shouldn't be remembered as the user's preferred order
+		if len(order) != len(byName) {
+			return
+		}
+
+		cp.Order = order
+		SavePrefs(ws)
+	})
+}
+
+func countVisibleColumns(cols map[string]*gtk.TreeViewColumn) int {
+	n := 0
+	for _, col := range cols {
+		if col.GetVisible() {
+			n++
+		}
+	}
+
+	return n
+}
@@ -1,15 +1,15 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charles-m-knox/gtk-finance-planner/constants"
 	"github.com/charles-m-knox/gtk-finance-planner/oldutil"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
 	"github.com/charles-m-knox/gtk-finance-planner/state"
 
 	lib "github.com/charles-m-knox/finance-planner-lib"
@@ -34,7 +34,7 @@ func DelConfItem(ws *state.WinState) {
 			continue
 		}
 
-		lib.RemoveTXByID(ws.TX, id)
+		planner.RemoveTXByID(ws.TX, id)
 	}
 
 	ClearAllSelections(ws)
@@ -45,7 +45,7 @@ func DelConfItem(ws *state.WinState) {
 
 func AddConfItem(ws *state.WinState) {
 	now := time.Now()
-	n := lib.GetNewTX(now)
+	n := planner.GetNewTX(now, *ws.TX)
 
 	*ws.TX = append(*ws.TX, n)
 
@@ -73,7 +73,7 @@ func CloneConfItem(ws *state.WinState) {
 			continue
 		}
 
-		i, err := lib.GetTXByID(ws.TX, id)
+		i, err := planner.GetTXByID(ws.TX, id)
 		if err != nil {
 			log.Printf("config item with id=%v was missing", id)
 			continue
@@ -93,6 +93,10 @@ func CloneConfItem(ws *state.WinState) {
 		(*ws.TX)[j].ID = uuid.New()
 	}
 
+	// clones share the manual order of their original, so renumbering places
+	// each clone directly after the item it was cloned from
+	planner.NormalizeOrder(*ws.TX)
+
 	ClearAllSelections(ws)
 
 	UpdateResults(ws, false)
@@ -100,16 +104,24 @@ func CloneConfItem(ws *state.WinState) {
 }
 
 // GetTXAsRow builds a GTK treeview-compatible set of fields & columns for a
-// provided TX definition.
-// TODO: refactor this to be more flexible. For example, it would be nice to
-// be able to hide/show some columns. This could maybe be done with a map.
-func GetTXAsRow(tx *lib.TX) (cells []interface{}, columns []int) {
+// provided TX definition. Every column is always populated; hiding columns is
+// done on the tree view itself (see SetupColumnPrefs). The TX's validation
+// problems (see ValidateConfig) determine which cells get highlighted.
+func GetTXAsRow(ws *state.WinState, tx *planner.TX) (cells []interface{}, columns []int) {
+	problems := ws.ProblemCells[tx.ID]
+	now := time.Now()
+
 	cells = []interface{}{
-		lib.FormatAsCurrency(tx.Amount), // tx.MarkupCurrency(lib.CurrencyMarkup(tx.Amount)),
+		tx.Order,
+		planner.GetAmountText(tx), // tx.MarkupCurrency(lib.CurrencyMarkup(tx.Amount)),
+		planner.GetAmountScheduleText(tx),
+		planner.GetSeasonalAmountsText(tx),
+		planner.GetLinkText(tx),
 		tx.Active,
 		tx.Name,                 // tx.MarkupText(tx.Name),
 		tx.Frequency,            // tx.MarkupText(tx.Frequency),
 		fmt.Sprint(tx.Interval), // tx.MarkupText(fmt.Sprint(tx.Interval)),
+		tx.MonthlyRule,
 		tx.Weekdays[constants.WeekdayMondayInt],
 		tx.Weekdays[constants.WeekdayTuesdayInt],
 		tx.Weekdays[constants.WeekdayWednesdayInt],
@@ -119,10 +131,20 @@ func GetTXAsRow(tx *lib.TX) (cells []interface{}, columns []int) {
 		tx.Weekdays[constants.WeekdaySundayInt],
 		fmt.Sprintf("%v-%v-%v", tx.StartsYear, tx.StartsMonth, tx.StartsDay), // tx.MarkupText(fmt.Sprintf("%v-%v-%v", tx.StartsYear, tx.StartsMonth, tx.StartsDay)),
 		fmt.Sprintf("%v-%v-%v", tx.EndsYear, tx.EndsMonth, tx.EndsDay),       // tx.MarkupText(fmt.Sprintf("%v-%v-%v", tx.EndsYear, tx.EndsMonth, tx.EndsDay)),
+		GetRRuleText(tx.RRule),
+		planner.GetShiftText(tx.Shift),
+		planner.GetExceptionsText(tx.Exceptions),
 		tx.Note, // tx.MarkupText(tx.Note),
 		tx.ID,
 		tx.CreatedAt.Format(time.RFC3339),
 		tx.UpdatedAt.Format(time.RFC3339),
+		ws.Occurrences[tx.ID],
+		lib.FormatAsCurrency(planner.GetYearlyAmount(tx, now)),
+		lib.FormatAsCurrency(planner.GetMonthlyAmount(tx, now)),
+	}
+
+	for _, column := range constants.ConfigColumns {
+		cells = append(cells, len(problems[column]) > 0)
 	}
 
 	columns = []int{}
@@ -133,14 +155,14 @@ func GetTXAsRow(tx *lib.TX) (cells []interface{}, columns []int) {
 	return cells, columns
 }
 
-func addConfigTreeRow(ls *gtk.ListStore, tx *lib.TX) error {
+func addConfigTreeRow(ws *state.WinState, tx *planner.TX) error {
 	// gets an iterator for a new row at the end of the list store
-	iter := ls.Append()
+	iter := ws.ConfigListStore.Append()
 
-	cells, columns := GetTXAsRow(tx)
+	cells, columns := GetTXAsRow(ws, tx)
 
 	// Set the contents of the list store row that the iterator represents
-	err := ls.Set(iter, columns, cells)
+	err := ws.ConfigListStore.Set(iter, columns, cells)
 	if err != nil {
 		return fmt.Errorf("unable to add config tree row: %v", err.Error())
 	}
@@ -151,11 +173,15 @@ func addConfigTreeRow(ls *gtk.ListStore, tx *lib.TX) error {
 // ConfigChange is triggered when the user makes a change in the config tree
 // view. This function is responsible for finding the underlying TX definition
 // that corresponds to the tree view UI item that was changed, by keying off of
-// the Order column (which is basically a unique ID). The `path` parameter is
-// the value provided from the cell edit event, which is typically something
-// like "1:2:5" or simply "1", depending on how the tree is constructed.
+// the ID column. The `path` parameter is the value provided from the cell edit
+// event, which is typically something like "1:2:5" or simply "1", depending on
+// how the tree is constructed.
+//
+// Checkbox cells report the state they had before being toggled, so their
+// values are inverted here before being handed off to ConfigChangeByID.
 func ConfigChange(ws *state.WinState, path string, column int, newValue interface{}) {
-	// start by iterating through the list store
+	id := ""
+
 	iterFn := func(model *gtk.TreeModel, searchPath *gtk.TreePath, iter *gtk.TreeIter) bool {
 		if searchPath.String() != path {
 			return false
@@ -164,141 +190,291 @@ func ConfigChange(ws *state.WinState, path string, column int, newValue interfac
 		val, err := oldutil.GetListStoreValue(ws.ConfigListStore, iter, constants.COLUMN_ID)
 		if err != nil {
 			log.Printf("config change error (list store value): %v", err.Error())
+			return true
 		}
 
-		id := val.(string)
+		id = val.(string)
 
-		// now that we've found the unique ID for this TX definition, we can
-		// proceed to find the actual TX definition
-		for i := range *ws.TX {
-			if (*ws.TX)[i].ID != id && id != "" {
-				continue
+		return true
+	}
+
+	ws.ConfigListStore.ForEach(iterFn)
+
+	if id == "" {
+		log.Printf("config change: no config item at path %v", path)
+		return
+	}
+
+	if v, ok := newValue.(bool); ok {
+		newValue = !v
+	}
+
+	ConfigChangeByID(ws, id, column, newValue)
+}
+
+// ConfigChangeByID applies a change to a single field (identified by its
+// config column, such as constants.COLUMN_AMOUNT) of the TX with the provided
+// ID, and then propagates the change to the config view, the detail panel and
+// the results. Text columns expect a string value, and checkbox columns expect
+// the new bool value.
+func ConfigChangeByID(ws *state.WinState, id string, column int, newValue interface{}) {
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("config change error: %v", err.Error())
+		return
+	}
+
+	tx := &(*ws.TX)[i]
+
+	// inputErr is set when the new value can't be parsed, in which case the
+	// TX is left unchanged and the reason is shown on the cell instead
+	var inputErr error
+
+	// reordered is set when the manual order of every TX has changed, in
+	// which case the whole config view is refreshed rather than just this row
+	reordered := false
+
+	switch column {
+	case constants.COLUMN_ORDER:
+		nv, err := planner.ParseOrder(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		// entering an order moves the row there, like dragging it does, so
+		// that no two rows end up with the same order
+		planner.MoveToOrder(*ws.TX, id, nv)
+
+		i, err = planner.GetTXByID(ws.TX, id)
+		if err != nil {
+			log.Printf("config change error: %v", err.Error())
+			return
+		}
+
+		tx = &(*ws.TX)[i]
+		reordered = true
+	case constants.COLUMN_AMOUNT:
+		// a seasonal or linked transaction's amount is shown as a range or
+		// with its source, and a formula's amount is shown as its result,
+		// which are left as they are unless something else is entered
+		if newValue.(string) == planner.GetAmountText(tx) || newValue.(string) == planner.GetAmountInputText(tx) {
+			break
+		}
+
+		if planner.IsFormula(newValue.(string)) {
+			formula := planner.NormalizeFormula(newValue.(string))
+			nv, err := planner.EvalFormula(formula, planner.GetVariableValues(ws.Variables))
+			if err != nil {
+				inputErr = fmt.Errorf("%v; %v", err.Error(), constants.MsgFormulaHint)
+				break
+			}
+
+			tx.Amount = nv
+			tx.Formula = formula
+		} else {
+			tx.Amount = int(lib.ParseDollarAmount(newValue.(string), false))
+			tx.Formula = ""
+		}
+
+		tx.SeasonalAmounts = nil
+		tx.Link = nil
+	case constants.COLUMN_AMOUNTS:
+		inputErr = tx.SetAmountSchedule(newValue.(string))
+	case constants.COLUMN_SEASONAL:
+		amounts, err := planner.ParseSeasonalAmounts(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		tx.SeasonalAmounts = amounts
+	case constants.COLUMN_LINK:
+		link, err := planner.ParseAmountLink(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		if link != nil {
+			link.ID, err = planner.ResolveLinkID(*ws.TX, link.ID)
+			if err != nil {
+				inputErr = err
+				break
 			}
+		}
+
+		tx.Link = link
+	case constants.COLUMN_ACTIVE:
+		nv := newValue.(bool)
+		tx.Active = nv
+	case constants.COLUMN_NAME:
+		nv := newValue.(string)
+		tx.Name = nv
+	case constants.COLUMN_FREQUENCY:
+		nv, err := planner.ParseFrequency(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		tx.Frequency = nv
+
+		// daily transactions ignore the weekdays
+		if nv == constants.DAILY {
+			tx.Weekdays = lib.GetWeekdaysMap()
+		}
+	case constants.COLUMN_INTERVAL:
+		nv, err := planner.ParseInterval(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		tx.Interval = nv
+	case constants.COLUMN_MONTHLYRULE:
+		r, err := planner.ParseMonthlyRule(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
 
-			// now we've found the actual TX definition and we can
-			// make changes to the TX and propagate it to the ListStore
-			switch column {
-			// case constants.COLUMN_ORDER:
-			// TODO: validate that the newValue is of int type
-			// before unsafe type assertion
-			// nv, err := strconv.ParseInt(newValue.(string), 10, 64)
-			// if err != nil {
-			// 	log.Printf(
-			// 		"failed to convert interval %v to int: %v",
-			// 		newValue,
-			// 		err.Error(),
-			// 	)
-			// }
-			// nvi := int(nv)
-			// if nvi <= 0 {
-			// 	nvi = 1
-			// }
-			// (*ws.TX)[i].Order = nvi
-			case constants.COLUMN_AMOUNT:
-				nv := int(lib.ParseDollarAmount(newValue.(string), false))
-				(*ws.TX)[i].Amount = nv
-			case constants.COLUMN_ACTIVE:
-				nv := !(newValue.(bool))
-				(*ws.TX)[i].Active = nv
-			case constants.COLUMN_NAME:
-				nv := newValue.(string)
-				(*ws.TX)[i].Name = nv
-			case constants.COLUMN_FREQUENCY:
-				// TODO: refactor for unit testability
-				nv := strings.ToUpper(strings.TrimSpace(newValue.(string)))
-				if nv == constants.Y {
-					nv = constants.YEARLY
-				}
-				if nv == constants.W {
-					nv = constants.WEEKLY
-				}
-				if nv == constants.M {
-					nv = constants.MONTHLY
-				}
-				if nv != constants.WEEKLY && nv != constants.MONTHLY && nv != constants.YEARLY {
-					(*ws.ShowMessageDialog)(constants.MsgInvalidRecurrence, gtk.MESSAGE_ERROR)
-					break
-				}
-
-				(*ws.TX)[i].Frequency = nv
-			case constants.COLUMN_INTERVAL:
-				nv, err := strconv.ParseInt(newValue.(string), 10, 64)
-				if err != nil {
-					(*ws.ShowMessageDialog)(fmt.Sprintf(
-						"failed to convert interval %v to int: %v",
-						newValue,
-						err.Error(),
-					), gtk.MESSAGE_ERROR)
-				}
-				if nv <= 0 {
-					nv = 1
-				}
-				(*ws.TX)[i].Interval = int(nv)
-			case constants.COLUMN_STARTS:
-				nvs := newValue.(string)
-				yr, mo, day := lib.ParseYearMonthDateString(
-					strings.TrimSpace(nvs),
-				)
-				(*ws.TX)[i].StartsYear = yr
-				(*ws.TX)[i].StartsMonth = mo
-				(*ws.TX)[i].StartsDay = day
-			case constants.COLUMN_ENDS:
-				// TODO: refactor similar code from above case
-				nvs := newValue.(string)
-				yr, mo, day := lib.ParseYearMonthDateString(
-					strings.TrimSpace(nvs),
-				)
-				(*ws.TX)[i].EndsYear = yr
-				(*ws.TX)[i].EndsMonth = mo
-				(*ws.TX)[i].EndsDay = day
-			case constants.COLUMN_NOTE:
-				nv := newValue.(string)
-				(*ws.TX)[i].Note = nv
-			default:
-				if oldutil.IsWeekday(constants.ConfigColumns[column]) {
-					weekday := oldutil.WeekdayIndex[constants.ConfigColumns[column]]
-					(*ws.TX)[i].Weekdays[weekday] = !(*ws.TX)[i].Weekdays[weekday]
-					break
-				}
-
-				log.Printf(
-					"warning: column id %v was modified, but there is no case to handle it",
-					column,
-				)
+		tx.MonthlyRule = ""
+		if r != nil {
+			tx.MonthlyRule = r.String()
+		}
+	case constants.COLUMN_STARTS:
+		yr, mo, day, err := planner.ParseDate(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
+
+		tx.StartsYear = yr
+		tx.StartsMonth = mo
+		tx.StartsDay = day
+	case constants.COLUMN_ENDS:
+		yr, mo, day, err := planner.ParseDate(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
 
+		tx.EndsYear = yr
+		tx.EndsMonth = mo
+		tx.EndsDay = day
+	case constants.COLUMN_RRULE:
+		nv := newValue.(string)
+		if strings.TrimSpace(nv) != "" {
+			_, err := planner.ParseRRule(nv, time.Now())
+			if err != nil {
+				inputErr = err
+				break
 			}
+		}
 
-			(*ws.TX)[i].UpdatedAt = time.Now()
+		tx.RRule = planner.NormalizeRRule(nv)
+	case constants.COLUMN_SHIFT:
+		shift, err := planner.ParseShift(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
+		}
 
-			cells, columns := GetTXAsRow(&(*ws.TX)[i])
-			ws.ConfigListStore.Set(iter, columns, cells)
+		tx.Shift = shift
+	case constants.COLUMN_EXCEPTIONS:
+		exceptions, err := planner.ParseExceptions(newValue.(string))
+		if err != nil {
+			inputErr = err
+			break
 		}
 
-		return true
+		tx.Exceptions = exceptions
+	case constants.COLUMN_NOTE:
+		nv := newValue.(string)
+		tx.Note = nv
+	default:
+		if oldutil.IsWeekday(constants.ConfigColumns[column]) {
+			if tx.Frequency == constants.DAILY {
+				inputErr = errors.New(constants.MsgDailyWeekdays)
+				break
+			}
+
+			weekday := oldutil.WeekdayIndex[constants.ConfigColumns[column]]
+			tx.Weekdays[weekday] = newValue.(bool)
+			break
+		}
+
+		log.Printf(
+			"warning: column id %v was modified, but there is no case to handle it",
+			column,
+		)
+
 	}
 
-	ws.ConfigListStore.ForEach(iterFn)
+	if ws.InputProblems == nil {
+		ws.InputProblems = make(planner.InputProblems)
+	}
 
-	// err := lib.ValidateTransactions(ws.TX)
-	// if err != nil {
-	// 	log.Printf("validation warning: %v", err.Error())
-	// 	// since there was a validation error, we have to update every single
-	// 	// item in the store
-	// 	err = SyncConfigListStore(ws)
-	// 	if err != nil {
-	// 		// an error occurred during the list store config after the user
-	// 		// made a change.
-	// 		(*ws.ShowMessageDialog)(fmt.Sprintf(
-	// 			"Error code %v - failed to sync after a config change: %v",
-	// 			constants.ErrorCodeSyncConfigListStore,
-	// 			err.Error(),
-	// 		), gtk.MESSAGE_ERROR)
-	// 	}
-	// }
+	if inputErr != nil {
+		ws.InputProblems.Set(tx.ID, constants.ConfigColumns[column], inputErr.Error())
+	} else {
+		ws.InputProblems.Clear(tx.ID, constants.ConfigColumns[column])
+		tx.UpdatedAt = time.Now()
+	}
+
+	if reordered {
+		selected := ws.SelectedConfIDs
+
+		err := SyncConfigListStore(ws)
+		if err != nil {
+			(*ws.ShowMessageDialog)(fmt.Sprintf(
+				"Error code %v - failed to sync after reordering: %v",
+				constants.ErrorCodeSyncConfigListStoreAfterReorder,
+				err.Error(),
+			), gtk.MESSAGE_ERROR)
+		}
+
+		SelectConfigRowsByID(ws, selected)
+	} else {
+		ValidateConfig(ws)
+		updateConfigRow(ws, tx)
+
+		// amounts that are linked to this one may have changed too
+		for _, dependent := range planner.GetLinkedDependents(*ws.TX, tx.ID) {
+			updateConfigRow(ws, dependent)
+		}
+	}
+
+	UpdateTotalsLabel(ws)
+	RefreshDetailPanel(ws)
 
 	UpdateResults(ws, false)
 }
 
+// updateConfigRow refreshes the config list store row that shows the provided
+// TX, if it's currently shown.
+func updateConfigRow(ws *state.WinState, tx *planner.TX) {
+	iterFn := func(model *gtk.TreeModel, searchPath *gtk.TreePath, iter *gtk.TreeIter) bool {
+		val, err := oldutil.GetListStoreValue(ws.ConfigListStore, iter, constants.COLUMN_ID)
+		if err != nil {
+			log.Printf("update config row: %v", err.Error())
+			return false
+		}
+
+		if val.(string) != tx.ID {
+			return false
+		}
+
+		cells, columns := GetTXAsRow(ws, tx)
+		ws.ConfigListStore.Set(iter, columns, cells)
+
+		return true
+	}
+
+	ws.ConfigListStore.ForEach(iterFn)
+}
+
 // SetConfigScrollPosition saves the current config scrolled window's vertical
 // and horizontal scrollbar positions, so that they can be recalled later. This
 // is typically followed by RestoreConfigScrollPosition. To leave a value
@@ -382,11 +558,32 @@ func RestoreConfigScrollPosition(ws *state.WinState) {
 	}()
 }
 
+// SetConfigSortColumn is called when a config column header is clicked. A
+// plain click sorts by that column alone, while ctrl+click adds the column as
+// a secondary sort key (or cycles its direction if it's already sorted).
 func SetConfigSortColumn(ws *state.WinState, column int) {
 	columnStr := constants.ConfigColumns[column]
-	next := lib.GetNextSort(ws.ConfigColumnSort, columnStr)
+	ws.ConfigSort = planner.GetNextSortKeys(ws.ConfigSort, columnStr, IsControlHeld())
+
+	err := SyncConfigListStore(ws)
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf(
+			"Error code %v - failed to sync after a column sort order change: %v",
+			constants.ErrorCodeSyncConfigListStoreAfterColumnSortChange,
+			err.Error(),
+		), gtk.MESSAGE_ERROR)
+	}
+
+	ClearAllSelections(ws)
+
+	UpdateResults(ws, false)
+}
 
-	ws.ConfigColumnSort = next
+// SetConfigSortManual switches the config view back to the manual order,
+// which is the order that was set by dragging rows around, by using
+// alt+up/alt+down, or by editing the Order column directly.
+func SetConfigSortManual(ws *state.WinState) {
+	ws.ConfigSort = []planner.SortKey{}
 	err := SyncConfigListStore(ws)
 	if err != nil {
 		(*ws.ShowMessageDialog)(fmt.Sprintf(
@@ -401,63 +598,192 @@ func SetConfigSortColumn(ws *state.WinState, column int) {
 	UpdateResults(ws, false)
 }
 
+// UpdateConfigSortHeaders shows the current sort in the config column headers.
+// Sorted columns get an arrow indicating their direction, and when sorting by
+// more than one column, the title also shows each column's sort priority.
+// Rows can only be dragged around while the manual sort is in use.
+func UpdateConfigSortHeaders(ws *state.WinState) {
+	if ws.ConfigTreeView != nil {
+		ws.ConfigTreeView.SetReorderable(len(ws.ConfigSort) == 0)
+	}
+
+	for id, col := range ws.ConfigColumns {
+		name := constants.ConfigColumns[id]
+		i := planner.GetSortKeyIndex(ws.ConfigSort, name)
+		if i < 0 {
+			col.SetSortIndicator(false)
+			col.SetTitle(name)
+			continue
+		}
+
+		order := gtk.SORT_ASCENDING
+		if ws.ConfigSort[i].Desc {
+			order = gtk.SORT_DESCENDING
+		}
+
+		col.SetSortIndicator(true)
+		col.SetSortOrder(order)
+
+		if len(ws.ConfigSort) > 1 {
+			col.SetTitle(fmt.Sprintf("%v %v", name, i+1))
+		} else {
+			col.SetTitle(name)
+		}
+	}
+}
+
+// GetConfigListStoreIDs returns the IDs of every row in the config list store,
+// in the same order that they are currently displayed.
+func GetConfigListStoreIDs(ws *state.WinState) []string {
+	ids := []string{}
+
+	iterFn := func(model *gtk.TreeModel, searchPath *gtk.TreePath, iter *gtk.TreeIter) bool {
+		val, err := oldutil.GetListStoreValue(ws.ConfigListStore, iter, constants.COLUMN_ID)
+		if err != nil {
+			log.Printf("get config list store ids: %v", err.Error())
+			return false
+		}
+
+		ids = append(ids, val.(string))
+
+		return false
+	}
+
+	ws.ConfigListStore.ForEach(iterFn)
+
+	return ids
+}
+
+// SelectConfigRowsByID selects every row in the config tree view whose TX ID
+// is present in ids. This is typically used to restore the selection after the
+// config list store has been re-synced.
+func SelectConfigRowsByID(ws *state.WinState, ids map[string]bool) {
+	if ws.ConfigTreeView == nil || len(ids) == 0 {
+		return
+	}
+
+	sel, err := ws.ConfigTreeView.GetSelection()
+	if err != nil {
+		log.Printf("failed to get config tree view selection: %v", err.Error())
+		return
+	}
+
+	iterFn := func(model *gtk.TreeModel, searchPath *gtk.TreePath, iter *gtk.TreeIter) bool {
+		val, err := oldutil.GetListStoreValue(ws.ConfigListStore, iter, constants.COLUMN_ID)
+		if err != nil {
+			log.Printf("select config rows by id: %v", err.Error())
+			return false
+		}
+
+		if ids[val.(string)] {
+			sel.SelectIter(iter)
+		}
+
+		return false
+	}
+
+	ws.ConfigListStore.ForEach(iterFn)
+}
+
+// ReorderConfItems takes the IDs of the visible config rows in their desired
+// order and makes that the new manual order. The config view is switched back
+// to the manual sort so that the user sees the order they just created.
+func ReorderConfItems(ws *state.WinState, ids []string) {
+	selected := ws.SelectedConfIDs
+
+	planner.ApplyVisibleOrder(*ws.TX, ids)
+
+	ws.ConfigSort = []planner.SortKey{}
+	err := SyncConfigListStore(ws)
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf(
+			"Error code %v - failed to sync after reordering: %v",
+			constants.ErrorCodeSyncConfigListStoreAfterReorder,
+			err.Error(),
+		), gtk.MESSAGE_ERROR)
+	}
+
+	SelectConfigRowsByID(ws, selected)
+
+	UpdateResults(ws, false)
+}
+
+// MoveConfItems moves the selected config rows up (delta < 0) or down
+// (delta > 0) by one row in the manual order of the rows that are currently
+// displayed. If the config view is sorted by another column, the rows are
+// moved within the manual order rather than the sorted one, so that the sort
+// doesn't replace the manual order.
+func MoveConfItems(ws *state.WinState, delta int) {
+	if len(ws.SelectedConfIDs) == 0 {
+		return
+	}
+
+	ids := GetConfigListStoreIDs(ws)
+	if len(ws.ConfigSort) > 0 {
+		ids = planner.GetManuallyOrderedIDs(*ws.TX, ids)
+	}
+
+	ReorderConfItems(ws, planner.MoveIDs(ids, ws.SelectedConfIDs, delta))
+}
+
 // getOrderColumn builds out an "Order" column, which is an integer column
 // that allows the user to control an unsorted default order of recurring
 // transactions in the config view
-// func getOrderColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
-// 	orderCellRenderer, err := gtk.CellRendererTextNew()
-// 	if err != nil {
-// 		return tvc, fmt.Errorf("unable to create amount column renderer: %v", err.Error())
-// 	}
-
-// 	orderColumn, err := gtk.TreeViewColumnNewWithAttribute(
-// 		constants.ColumnOrder,
-// 		orderCellRenderer,
-// 		"text",
-// 		constants.COLUMN_ORDER,
-// 	)
-// 	if err != nil {
-// 		return tvc, fmt.Errorf(
-// 			"unable to create amount cell column: %v",
-// 			err.Error(),
-// 		)
-// 	}
-
-// 	orderColumnBtn, err := orderColumn.GetButton()
-// 	if err != nil {
-// 		log.Printf(
-// 			"failed to get active column header button: %v",
-// 			err.Error(),
-// 		)
-// 	}
-
-// 	orderCellEditingStarted := func(
-// 		a *gtk.CellRendererText,
-// 		e *gtk.CellEditable,
-// 		path string,
-// 	) {
-// 		log.Println(constants.GtkSignalEditingStart, a, path)
-// 	}
-
-// 	orderCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
-// 		ConfigChange(ws, path, constants.COLUMN_ORDER, newText)
-// 	}
-// 	orderCellRenderer.SetProperty("editable", true)
-// 	orderCellRenderer.SetVisible(true)
-
-// 	orderCellRenderer.Connect(constants.GtkSignalEditingStart, orderCellEditingStarted)
-// 	orderCellRenderer.Connect(constants.GtkSignalEdited, orderCellEditingFinished)
-
-// 	orderColumn.SetResizable(true)
-// 	orderColumn.SetClickable(true)
-// 	orderColumn.SetVisible(true)
-
-// 	orderColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
-// 		SetConfigSortColumn(ws, constants.COLUMN_ORDER)
-// 	})
-
-// 	return orderColumn, nil
-// }
+func getOrderColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	orderCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create order column renderer: %v", err.Error())
+	}
+
+	orderColumn, err := gtk.TreeViewColumnNewWithAttribute(
+		constants.ColumnOrder,
+		orderCellRenderer,
+		"text",
+		constants.COLUMN_ORDER,
+	)
+	if err != nil {
+		return tvc, fmt.Errorf(
+			"unable to create order cell column: %v",
+			err.Error(),
+		)
+	}
+
+	orderColumnBtn, err := orderColumn.GetButton()
+	if err != nil {
+		log.Printf(
+			"failed to get order column header button: %v",
+			err.Error(),
+		)
+	}
+
+	orderCellEditingStarted := func(
+		a *gtk.CellRendererText,
+		e *gtk.CellEditable,
+		path string,
+	) {
+		// log.Println(constants.GtkSignalEditingStart, a, path)
+	}
+
+	orderCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_ORDER, newText)
+	}
+	orderCellRenderer.SetProperty("editable", true)
+	orderCellRenderer.SetVisible(true)
+
+	orderCellRenderer.Connect(constants.GtkSignalEditingStart, orderCellEditingStarted)
+	orderCellRenderer.Connect(constants.GtkSignalEdited, orderCellEditingFinished)
+
+	orderColumn.SetResizable(true)
+	addProblemHighlight(orderColumn, &orderCellRenderer.CellRenderer, constants.COLUMN_ORDER)
+	orderColumn.SetClickable(true)
+	orderColumn.SetVisible(true)
+
+	orderColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_ORDER)
+	})
+
+	return orderColumn, nil
+}
 
 // getAmountColumn builds out an "Amount" column, which is an integer column
 // that allows the user to input a positive or negative cash amount and it will
@@ -486,6 +812,7 @@ func getAmountColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 		)
 	}
 	amtColumn.SetResizable(true)
+	addProblemHighlight(amtColumn, &amtCellRenderer.CellRenderer, constants.COLUMN_AMOUNT)
 	amtColumn.SetClickable(true)
 	amtColumn.SetVisible(true)
 
@@ -500,6 +827,108 @@ func getAmountColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 	return amtColumn, nil
 }
 
+// getAmountsColumn builds out an "Amount changes" column, which is a string
+// column that allows the user to change a transaction's amount over time,
+// either with dated amounts such as "$-1900.00 from 2027-06-01" or with an
+// escalation rule such as "+4% every 12 months from 2027-06-01".
+func getAmountsColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	amountsCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_AMOUNTS, newText)
+	}
+	amountsCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Amount changes column renderer: %v", err.Error())
+	}
+	amountsCellRenderer.SetProperty("editable", true)
+	amountsCellRenderer.SetVisible(true)
+	amountsCellRenderer.Connect(constants.GtkSignalEdited, amountsCellEditingFinished)
+	amountsColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnAmounts, amountsCellRenderer, "text", constants.COLUMN_AMOUNTS)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Amount changes cell column: %v", err.Error())
+	}
+	amountsColumn.SetResizable(true)
+	addProblemHighlight(amountsColumn, &amountsCellRenderer.CellRenderer, constants.COLUMN_AMOUNTS)
+	amountsColumn.SetClickable(true)
+	amountsColumn.SetVisible(true)
+	amountsColumnBtn, err := amountsColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get amount changes column header button: %v", err.Error())
+	}
+	amountsColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_AMOUNTS)
+	})
+
+	return amountsColumn, nil
+}
+
+// getSeasonalColumn builds out a "Seasonal amounts" column, which is a string
+// column that allows the user to give a transaction a different amount for
+// each month, such as "Jan $-180.00; Feb $-160.00; ...". See also
+// ShowSeasonalAmountsEditor.
+func getSeasonalColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	seasonalCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_SEASONAL, newText)
+	}
+	seasonalCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Seasonal amounts column renderer: %v", err.Error())
+	}
+	seasonalCellRenderer.SetProperty("editable", true)
+	seasonalCellRenderer.SetVisible(true)
+	seasonalCellRenderer.Connect(constants.GtkSignalEdited, seasonalCellEditingFinished)
+	seasonalColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnSeasonal, seasonalCellRenderer, "text", constants.COLUMN_SEASONAL)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Seasonal amounts cell column: %v", err.Error())
+	}
+	seasonalColumn.SetResizable(true)
+	addProblemHighlight(seasonalColumn, &seasonalCellRenderer.CellRenderer, constants.COLUMN_SEASONAL)
+	seasonalColumn.SetClickable(true)
+	seasonalColumn.SetVisible(true)
+	seasonalColumnBtn, err := seasonalColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get seasonal amounts column header button: %v", err.Error())
+	}
+	seasonalColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_SEASONAL)
+	})
+
+	return seasonalColumn, nil
+}
+
+// getLinkColumn builds out a "Linked to" column, which is a string column that
+// allows the user to make a transaction's amount a percentage of another
+// transaction's amount, such as "10% of Paycheck rounded to $1.00". The other
+// transaction can be entered by name, but it's saved by its ID.
+func getLinkColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	linkCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_LINK, newText)
+	}
+	linkCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Linked to column renderer: %v", err.Error())
+	}
+	linkCellRenderer.SetProperty("editable", true)
+	linkCellRenderer.SetVisible(true)
+	linkCellRenderer.Connect(constants.GtkSignalEdited, linkCellEditingFinished)
+	linkColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnLink, linkCellRenderer, "text", constants.COLUMN_LINK)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Linked to cell column: %v", err.Error())
+	}
+	linkColumn.SetResizable(true)
+	addProblemHighlight(linkColumn, &linkCellRenderer.CellRenderer, constants.COLUMN_LINK)
+	linkColumn.SetClickable(true)
+	linkColumn.SetVisible(true)
+	linkColumnBtn, err := linkColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get linked to column header button: %v", err.Error())
+	}
+	linkColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_LINK)
+	})
+
+	return linkColumn, nil
+}
+
 // getActiveColumn builds out an "Active" column, which is a boolean column
 // that allows the user to enable/disable a recurring transaction
 func getActiveColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
@@ -551,6 +980,7 @@ func getNameColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 		return tvc, fmt.Errorf("unable to create Name cell column: %v", err.Error())
 	}
 	nameColumn.SetResizable(true)
+	addProblemHighlight(nameColumn, &nameCellRenderer.CellRenderer, constants.COLUMN_NAME)
 	nameColumn.SetClickable(true)
 	nameColumn.SetVisible(true)
 	nameColumnHeaderBtn, err := nameColumn.GetButton()
@@ -565,9 +995,11 @@ func getNameColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 }
 
 // getFrequencyColumn builds out a "Frequency" column, which is a string
-// column that allows the user to specify monthly/weekly/yearly recurrences
+// column that allows the user to specify daily/weekly/monthly/yearly
+// recurrences
 // TODO: make this form of input more user friendly - currently it requires
-// users to just simply type "MONTHLY"/"YEARLY"/"WEEKLY"
+// users to just simply type "DAILY"/"WEEKLY"/"MONTHLY"/"YEARLY" (or a shorthand
+// such as "d")
 func getFrequencyColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 	freqCellEditingStarted := func(a *gtk.CellRendererText, e *gtk.CellEditable, path string) {
 		// log.Println(constants.GtkSignalEditingStart, a, path)
@@ -589,6 +1021,7 @@ func getFrequencyColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error)
 		return tvc, fmt.Errorf("unable to create Frequency cell column: %v", err.Error())
 	}
 	freqColumn.SetResizable(true)
+	addProblemHighlight(freqColumn, &freqCellRenderer.CellRenderer, constants.COLUMN_FREQUENCY)
 	freqColumn.SetClickable(true)
 	freqColumn.SetVisible(true)
 
@@ -633,6 +1066,7 @@ func getIntervalColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error)
 		return tvc, fmt.Errorf("unable to create Interval cell column: %v", err.Error())
 	}
 	intervalColumn.SetResizable(true)
+	addProblemHighlight(intervalColumn, &intervalCellRenderer.CellRenderer, constants.COLUMN_INTERVAL)
 	intervalColumn.SetClickable(true)
 	intervalColumn.SetVisible(true)
 	intervalColumnBtn, err := intervalColumn.GetButton()
@@ -646,6 +1080,39 @@ func getIntervalColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error)
 	return intervalColumn, nil
 }
 
+// getMonthlyRuleColumn builds out a "Monthly on" column, which is a string
+// column that allows the user to choose which day of the month a monthly
+// transaction occurs on, such as "first Monday" or "last business day".
+func getMonthlyRuleColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	monthlyRuleCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_MONTHLYRULE, newText)
+	}
+	monthlyRuleCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Monthly rule column renderer: %v", err.Error())
+	}
+	monthlyRuleCellRenderer.SetProperty("editable", true)
+	monthlyRuleCellRenderer.SetVisible(true)
+	monthlyRuleCellRenderer.Connect(constants.GtkSignalEdited, monthlyRuleCellEditingFinished)
+	monthlyRuleColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnMonthlyRule, monthlyRuleCellRenderer, "text", constants.COLUMN_MONTHLYRULE)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Monthly rule cell column: %v", err.Error())
+	}
+	monthlyRuleColumn.SetResizable(true)
+	addProblemHighlight(monthlyRuleColumn, &monthlyRuleCellRenderer.CellRenderer, constants.COLUMN_MONTHLYRULE)
+	monthlyRuleColumn.SetClickable(true)
+	monthlyRuleColumn.SetVisible(true)
+	monthlyRuleColumnBtn, err := monthlyRuleColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get monthly rule column header button: %v", err.Error())
+	}
+	monthlyRuleColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_MONTHLYRULE)
+	})
+
+	return monthlyRuleColumn, nil
+}
+
 // getStartsColumn builds out a "Starts" column, which is a string column that
 // allows the user to type in a starting date, such as 2020-02-01.
 // TODO: refactoring and cleanup
@@ -670,6 +1137,7 @@ func getStartsColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 		return tvc, fmt.Errorf("unable to create Starts cell column: %v", err.Error())
 	}
 	startsColumn.SetResizable(true)
+	addProblemHighlight(startsColumn, &startsCellRenderer.CellRenderer, constants.COLUMN_STARTS)
 	startsColumn.SetClickable(true)
 	startsColumn.SetVisible(true)
 	startsColumnHeaderBtn, err := startsColumn.GetButton()
@@ -707,6 +1175,7 @@ func getEndsColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 		return tvc, fmt.Errorf("unable to create Ends cell column: %v", err.Error())
 	}
 	endsColumn.SetResizable(true)
+	addProblemHighlight(endsColumn, &endsCellRenderer.CellRenderer, constants.COLUMN_ENDS)
 	endsColumn.SetClickable(true)
 	endsColumn.SetVisible(true)
 	endsColumnHeaderBtn, err := endsColumn.GetButton()
@@ -720,6 +1189,107 @@ func getEndsColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 	return endsColumn, nil
 }
 
+// getRRuleColumn builds out an "RRule" column, which is a string column that
+// allows the user to type in an iCalendar recurrence rule that overrides the
+// other recurrence columns. Rules with several properties are shown (and can be
+// typed) on one line, separated by spaces. See also ShowRRuleEditor.
+func getRRuleColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	rruleCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_RRULE, newText)
+	}
+	rruleCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create RRule column renderer: %v", err.Error())
+	}
+	rruleCellRenderer.SetProperty("editable", true)
+	rruleCellRenderer.SetVisible(true)
+	rruleCellRenderer.Connect(constants.GtkSignalEdited, rruleCellEditingFinished)
+	rruleColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnRRule, rruleCellRenderer, "text", constants.COLUMN_RRULE)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create RRule cell column: %v", err.Error())
+	}
+	rruleColumn.SetResizable(true)
+	addProblemHighlight(rruleColumn, &rruleCellRenderer.CellRenderer, constants.COLUMN_RRULE)
+	rruleColumn.SetClickable(true)
+	rruleColumn.SetVisible(true)
+	rruleColumnBtn, err := rruleColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get rrule column header button: %v", err.Error())
+	}
+	rruleColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_RRULE)
+	})
+
+	return rruleColumn, nil
+}
+
+// getShiftColumn builds out a "Weekends/holidays" column, which is a string
+// column that allows the user to choose whether a transaction that falls on a
+// weekend or holiday is moved to the previous or next business day.
+func getShiftColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	shiftCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_SHIFT, newText)
+	}
+	shiftCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Weekends/holidays column renderer: %v", err.Error())
+	}
+	shiftCellRenderer.SetProperty("editable", true)
+	shiftCellRenderer.SetVisible(true)
+	shiftCellRenderer.Connect(constants.GtkSignalEdited, shiftCellEditingFinished)
+	shiftColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnShift, shiftCellRenderer, "text", constants.COLUMN_SHIFT)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Weekends/holidays cell column: %v", err.Error())
+	}
+	shiftColumn.SetResizable(true)
+	addProblemHighlight(shiftColumn, &shiftCellRenderer.CellRenderer, constants.COLUMN_SHIFT)
+	shiftColumn.SetClickable(true)
+	shiftColumn.SetVisible(true)
+	shiftColumnBtn, err := shiftColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get weekends/holidays column header button: %v", err.Error())
+	}
+	shiftColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_SHIFT)
+	})
+
+	return shiftColumn, nil
+}
+
+// getExceptionsColumn builds out an "Exceptions" column, which is a string
+// column that allows the user to change individual occurrences of a
+// transaction, such as "2026-11-01 skip; 2026-12-01 $-250.00". See also
+// ShowExceptionEditor.
+func getExceptionsColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
+	exceptionsCellEditingFinished := func(a *gtk.CellRendererText, path string, newText string) {
+		ConfigChange(ws, path, constants.COLUMN_EXCEPTIONS, newText)
+	}
+	exceptionsCellRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Exceptions column renderer: %v", err.Error())
+	}
+	exceptionsCellRenderer.SetProperty("editable", true)
+	exceptionsCellRenderer.SetVisible(true)
+	exceptionsCellRenderer.Connect(constants.GtkSignalEdited, exceptionsCellEditingFinished)
+	exceptionsColumn, err := gtk.TreeViewColumnNewWithAttribute(constants.ColumnExceptions, exceptionsCellRenderer, "text", constants.COLUMN_EXCEPTIONS)
+	if err != nil {
+		return tvc, fmt.Errorf("unable to create Exceptions cell column: %v", err.Error())
+	}
+	exceptionsColumn.SetResizable(true)
+	addProblemHighlight(exceptionsColumn, &exceptionsCellRenderer.CellRenderer, constants.COLUMN_EXCEPTIONS)
+	exceptionsColumn.SetClickable(true)
+	exceptionsColumn.SetVisible(true)
+	exceptionsColumnBtn, err := exceptionsColumn.GetButton()
+	if err != nil {
+		log.Printf("failed to get exceptions column header button: %v", err.Error())
+	}
+	exceptionsColumnBtn.ToWidget().Connect(constants.GtkSignalClicked, func() {
+		SetConfigSortColumn(ws, constants.COLUMN_EXCEPTIONS)
+	})
+
+	return exceptionsColumn, nil
+}
+
 // getNotesColumn builds out a "Note" column, which is a string column that
 // allows the user to type in whatever notes they want for a recurring
 // transaction.
@@ -746,6 +1316,7 @@ func getNotesColumn(ws *state.WinState) (tvc *gtk.TreeViewColumn, err error) {
 		return tvc, fmt.Errorf("unable to create Notes cell column: %v", err.Error())
 	}
 	notesColumn.SetResizable(true)
+	addProblemHighlight(notesColumn, &notesCellRenderer.CellRenderer, constants.COLUMN_NOTE)
 	notesColumn.SetClickable(true)
 	notesColumn.SetVisible(true)
 	notesColumnHeaderBtn, err := notesColumn.GetButton()
@@ -779,6 +1350,7 @@ func getReadOnlyColumn(ws *state.WinState, name string, id int) (tvc *gtk.TreeVi
 		)
 	}
 	col.SetResizable(true)
+	addProblemHighlight(col, &rend.CellRenderer, id)
 	col.SetClickable(true)
 	col.SetVisible(true)
 	header, err := col.GetButton()
@@ -798,11 +1370,11 @@ func setupConfigTreeView(ws *state.WinState) (tv *gtk.TreeView, err error) {
 		return tv, fmt.Errorf("unable to create config tree view: %v", err.Error())
 	}
 
-	// orderColumn, err := getOrderColumn(ws)
-	// if err != nil {
-	// 	return tv, fmt.Errorf("failed to create config order column: %v", err.Error())
-	// }
-	// treeView.AppendColumn(orderColumn)
+	orderColumn, err := getOrderColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config order column: %v", err.Error())
+	}
+	treeView.AppendColumn(orderColumn)
 
 	amtColumn, err := getAmountColumn(ws)
 	if err != nil {
@@ -810,6 +1382,24 @@ func setupConfigTreeView(ws *state.WinState) (tv *gtk.TreeView, err error) {
 	}
 	treeView.AppendColumn(amtColumn)
 
+	amountsColumn, err := getAmountsColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config amount changes column: %v", err.Error())
+	}
+	treeView.AppendColumn(amountsColumn)
+
+	seasonalColumn, err := getSeasonalColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config seasonal amounts column: %v", err.Error())
+	}
+	treeView.AppendColumn(seasonalColumn)
+
+	linkColumn, err := getLinkColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config linked to column: %v", err.Error())
+	}
+	treeView.AppendColumn(linkColumn)
+
 	activeColumn, err := getActiveColumn(ws)
 	if err != nil {
 		return tv, fmt.Errorf("failed to create config active column: %v", err.Error())
@@ -834,6 +1424,12 @@ func setupConfigTreeView(ws *state.WinState) (tv *gtk.TreeView, err error) {
 	}
 	treeView.AppendColumn(intervalColumn)
 
+	monthlyRuleColumn, err := getMonthlyRuleColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config monthly rule column: %v", err.Error())
+	}
+	treeView.AppendColumn(monthlyRuleColumn)
+
 	// weekday columns
 	for i := range constants.Weekdays {
 		// prevents pointers from changing which column is referred to
@@ -875,6 +1471,24 @@ func setupConfigTreeView(ws *state.WinState) (tv *gtk.TreeView, err error) {
 	}
 	treeView.AppendColumn(endsColumn)
 
+	rruleColumn, err := getRRuleColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config rrule column: %v", err.Error())
+	}
+	treeView.AppendColumn(rruleColumn)
+
+	shiftColumn, err := getShiftColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config weekends/holidays column: %v", err.Error())
+	}
+	treeView.AppendColumn(shiftColumn)
+
+	exceptionsColumn, err := getExceptionsColumn(ws)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config exceptions column: %v", err.Error())
+	}
+	treeView.AppendColumn(exceptionsColumn)
+
 	notesColumn, err := getNotesColumn(ws)
 	if err != nil {
 		return tv, fmt.Errorf("failed to create config notes column: %v", err.Error())
@@ -899,21 +1513,77 @@ func setupConfigTreeView(ws *state.WinState) (tv *gtk.TreeView, err error) {
 	}
 	treeView.AppendColumn(updatedColumn)
 
+	occurrencesColumn, err := getReadOnlyColumn(ws, constants.ColumnOccurrences, constants.COLUMN_OCCURRENCES)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config occurrences column: %v", err.Error())
+	}
+	treeView.AppendColumn(occurrencesColumn)
+
+	yearlyColumn, err := getReadOnlyColumn(ws, constants.ColumnYearly, constants.COLUMN_YEARLY)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config yearly column: %v", err.Error())
+	}
+	treeView.AppendColumn(yearlyColumn)
+
+	monthlyColumn, err := getReadOnlyColumn(ws, constants.ColumnMonthly, constants.COLUMN_MONTHLY)
+	if err != nil {
+		return tv, fmt.Errorf("failed to create config monthly column: %v", err.Error())
+	}
+	treeView.AppendColumn(monthlyColumn)
+
 	treeView.SetModel(ws.ConfigListStore)
 
+	// keep track of each column so that its header can be updated later on,
+	// e.g. to indicate the current sort
+	ws.ConfigColumns = make(map[int]*gtk.TreeViewColumn)
+	for i := range constants.ConfigColumns {
+		ws.ConfigColumns[i] = treeView.GetColumn(i)
+	}
+
+	SetupColumnPrefs(ws, treeView, constants.ConfigColumns, &ws.Prefs.ConfigColumns)
+
+	// rows can be dragged around to change the manual order; the list store
+	// reorders itself, and once the drag is done, its new order is adopted
+	// as the manual order. Dragging is only possible in the manual sort (see
+	// UpdateConfigSortHeaders), since the displayed order would otherwise
+	// replace the manual order.
+	var dragStartIDs []string
+
+	treeView.SetReorderable(len(ws.ConfigSort) == 0)
+	treeView.Connect(constants.GtkSignalDragBegin, func() {
+		dragStartIDs = GetConfigListStoreIDs(ws)
+	})
+	treeView.Connect(constants.GtkSignalDragEnd, func() {
+		ids := GetConfigListStoreIDs(ws)
+		if len(ws.ConfigSort) > 0 || strings.Join(ids, ",") == strings.Join(dragStartIDs, ",") {
+			return
+		}
+
+		ReorderConfItems(ws, ids)
+	})
+
+	treeView.Connect(constants.GtkSignalKeyPressEvent, ConfigKeyPressHandler(ws))
+
+	treeView.SetProperty("has-tooltip", true)
+	treeView.Connect(constants.GtkSignalQueryTooltip, configQueryTooltip(ws))
+
 	return treeView, nil
 }
 
 // GetNewConfigListStore creates a list store. This is what holds the data
 // that will be shown on our config tree view
 func GetNewConfigListStore() (ls *gtk.ListStore, err error) {
-	ls, err = gtk.ListStoreNew(
-		// glib.TYPE_INT,
+	types := []glib.Type{
+		glib.TYPE_INT,     // COLUMN_ORDER
 		glib.TYPE_STRING,  // COLUMN_AMOUNT
+		glib.TYPE_STRING,  // COLUMN_AMOUNTS
+		glib.TYPE_STRING,  // COLUMN_SEASONAL
+		glib.TYPE_STRING,  // COLUMN_LINK
 		glib.TYPE_BOOLEAN, // COLUMN_ACTIVE
 		glib.TYPE_STRING,  // COLUMN_NAME
 		glib.TYPE_STRING,  // COLUMN_FREQUENCY
 		glib.TYPE_STRING,  // COLUMN_INTERVAL
+		glib.TYPE_STRING,  // COLUMN_MONTHLYRULE
 		glib.TYPE_BOOLEAN, // COLUMN_MONDAY
 		glib.TYPE_BOOLEAN, // COLUMN_TUESDAY
 		glib.TYPE_BOOLEAN, // COLUMN_WEDNESDAY
@@ -923,11 +1593,24 @@ func GetNewConfigListStore() (ls *gtk.ListStore, err error) {
 		glib.TYPE_BOOLEAN, // COLUMN_SUNDAY
 		glib.TYPE_STRING,  // COLUMN_STARTS
 		glib.TYPE_STRING,  // COLUMN_ENDS
+		glib.TYPE_STRING,  // COLUMN_RRULE
+		glib.TYPE_STRING,  // COLUMN_SHIFT
+		glib.TYPE_STRING,  // COLUMN_EXCEPTIONS
 		glib.TYPE_STRING,  // COLUMN_NOTE
 		glib.TYPE_STRING,  // COLUMN_ID
 		glib.TYPE_STRING,  // COLUMN_CREATEDAT
 		glib.TYPE_STRING,  // COLUMN_UPDATEDAT
-	)
+		glib.TYPE_INT,     // COLUMN_OCCURRENCES
+		glib.TYPE_STRING,  // COLUMN_YEARLY
+		glib.TYPE_STRING,  // COLUMN_MONTHLY
+	}
+
+	// COLUMN_PROBLEMS+n
+	for range constants.ConfigColumns {
+		types = append(types, glib.TYPE_BOOLEAN)
+	}
+
+	ls, err = gtk.ListStoreNew(types...)
 	if err != nil {
 		return ls, fmt.Errorf("unable to create config list store: %v", err.Error())
 	}
@@ -936,127 +1619,11 @@ func GetNewConfigListStore() (ls *gtk.ListStore, err error) {
 }
 
 func SyncConfigListStore(ws *state.WinState) error {
+	ValidateConfig(ws)
+
 	// sort first
-	sort.SliceStable(
-		*ws.TX,
-		func(i, j int) bool {
-			tj := (*ws.TX)[j]
-			ti := (*ws.TX)[i]
-
-			switch ws.ConfigColumnSort {
-
-			// invisible order column (default when no sort is set)
-			case constants.None:
-				// return tj.Order > ti.Order
-				return tj.CreatedAt.After(ti.CreatedAt)
-
-			// Order
-			// case fmt.Sprintf("%v%v", constants.ColumnOrder, constants.Asc):
-			// 	return tj.Order > ti.Order
-			// case fmt.Sprintf("%v%v", constants.ColumnOrder, constants.Desc):
-			// 	return ti.Order > tj.Order
-
-			// active
-			case fmt.Sprintf("%v%v", constants.ColumnActive, constants.Asc):
-				return tj.Active
-			case fmt.Sprintf("%v%v", constants.ColumnActive, constants.Desc):
-				return ti.Active
-
-			// weekdays
-			case fmt.Sprintf("%v%v", constants.WeekdayMonday, constants.Asc):
-				return tj.Weekdays[constants.WeekdayMondayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayMonday, constants.Desc):
-				return ti.Weekdays[constants.WeekdayMondayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayTuesday, constants.Asc):
-				return tj.Weekdays[constants.WeekdayTuesdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayTuesday, constants.Desc):
-				return ti.Weekdays[constants.WeekdayTuesdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayWednesday, constants.Asc):
-				return tj.Weekdays[constants.WeekdayWednesdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayWednesday, constants.Desc):
-				return ti.Weekdays[constants.WeekdayWednesdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayThursday, constants.Asc):
-				return tj.Weekdays[constants.WeekdayThursdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayThursday, constants.Desc):
-				return ti.Weekdays[constants.WeekdayThursdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayFriday, constants.Asc):
-				return tj.Weekdays[constants.WeekdayFridayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdayFriday, constants.Desc):
-				return ti.Weekdays[constants.WeekdayFridayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdaySaturday, constants.Asc):
-				return tj.Weekdays[constants.WeekdaySaturdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdaySaturday, constants.Desc):
-				return ti.Weekdays[constants.WeekdaySaturdayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdaySunday, constants.Asc):
-				return tj.Weekdays[constants.WeekdaySundayInt]
-			case fmt.Sprintf("%v%v", constants.WeekdaySunday, constants.Desc):
-				return ti.Weekdays[constants.WeekdaySundayInt]
-
-			// other columns
-			case fmt.Sprintf("%v%v", constants.ColumnAmount, constants.Asc):
-				return tj.Amount > ti.Amount
-			case fmt.Sprintf("%v%v", constants.ColumnAmount, constants.Desc):
-				return ti.Amount > tj.Amount
-
-			case fmt.Sprintf("%v%v", constants.ColumnFrequency, constants.Asc):
-				return tj.Frequency > ti.Frequency
-			case fmt.Sprintf("%v%v", constants.ColumnFrequency, constants.Desc):
-				return ti.Frequency > tj.Frequency
-
-			case fmt.Sprintf("%v%v", constants.ColumnInterval, constants.Asc):
-				return tj.Interval > ti.Interval
-			case fmt.Sprintf("%v%v", constants.ColumnInterval, constants.Desc):
-				return ti.Interval > tj.Interval
-			case fmt.Sprintf("%v%v", constants.ColumnNote, constants.Asc):
-
-				return strings.ToLower(tj.Note) > strings.ToLower(ti.Note)
-			case fmt.Sprintf("%v%v", constants.ColumnNote, constants.Desc):
-				return strings.ToLower(ti.Note) > strings.ToLower(tj.Note)
-
-			case fmt.Sprintf("%v%v", constants.ColumnName, constants.Asc):
-				return strings.ToLower(tj.Name) > strings.ToLower(ti.Name)
-			case fmt.Sprintf("%v%v", constants.ColumnName, constants.Desc):
-				return strings.ToLower(ti.Name) > strings.ToLower(tj.Name)
-
-			case fmt.Sprintf("%v%v", constants.ColumnID, constants.Asc):
-				return strings.ToLower(tj.ID) > strings.ToLower(ti.ID)
-			case fmt.Sprintf("%v%v", constants.ColumnID, constants.Desc):
-				return strings.ToLower(ti.ID) > strings.ToLower(tj.ID)
-
-			case fmt.Sprintf("%v%v", constants.ColumnCreatedAt, constants.Asc):
-				return tj.CreatedAt.After(ti.CreatedAt)
-			case fmt.Sprintf("%v%v", constants.ColumnCreatedAt, constants.Desc):
-				return ti.CreatedAt.Before(tj.CreatedAt)
-
-			case fmt.Sprintf("%v%v", constants.ColumnUpdatedAt, constants.Asc):
-				return tj.UpdatedAt.After(ti.UpdatedAt)
-			case fmt.Sprintf("%v%v", constants.ColumnUpdatedAt, constants.Desc):
-				return ti.UpdatedAt.Before(tj.UpdatedAt)
-
-			case fmt.Sprintf("%v%v", constants.ColumnStarts, constants.Asc):
-				ist := fmt.Sprintf("%v-%v-%v", tj.StartsYear, tj.StartsMonth, tj.StartsDay)
-				jst := fmt.Sprintf("%v-%v-%v", ti.StartsYear, ti.StartsMonth, ti.StartsDay)
-				return ist > jst
-			case fmt.Sprintf("%v%v", constants.ColumnStarts, constants.Desc):
-				ist := fmt.Sprintf("%v-%v-%v", tj.StartsYear, tj.StartsMonth, tj.StartsDay)
-				jst := fmt.Sprintf("%v-%v-%v", ti.StartsYear, ti.StartsMonth, ti.StartsDay)
-				return jst > ist
-
-			case fmt.Sprintf("%v%v", constants.ColumnEnds, constants.Asc):
-				jend := fmt.Sprintf("%v-%v-%v", tj.EndsYear, tj.EndsMonth, tj.EndsDay)
-				iend := fmt.Sprintf("%v-%v-%v", ti.EndsYear, ti.EndsMonth, ti.EndsDay)
-				return jend > iend
-			case fmt.Sprintf("%v%v", constants.ColumnEnds, constants.Desc):
-				jend := fmt.Sprintf("%v-%v-%v", tj.EndsYear, tj.EndsMonth, tj.EndsDay)
-				iend := fmt.Sprintf("%v-%v-%v", ti.EndsYear, ti.EndsMonth, ti.EndsDay)
-				return iend > jend
-
-			default:
-				return false
-				// return txs[j].Date.After(txs[i].Date)
-			}
-		},
-	)
+	planner.SortTX(*ws.TX, ws.ConfigSort, ws.Occurrences)
+	UpdateConfigSortHeaders(ws)
 
 	SetConfigScrollPosition(ws, -1, -1)
 
@@ -1069,13 +1636,14 @@ func SyncConfigListStore(ws *state.WinState) error {
 		if !tx.Active && ws.HideInactive == true {
 			continue
 		}
-		err := addConfigTreeRow(ws.ConfigListStore, &tx)
+		err := addConfigTreeRow(ws, &tx)
 		if err != nil {
 			return fmt.Errorf("failed to sync list store: %v", err.Error())
 		}
 	}
 
 	RestoreConfigScrollPosition(ws)
+	UpdateTotalsLabel(ws)
 
 	return nil
 }
@@ -1117,6 +1685,21 @@ func GetHideInactiveCheckbox(ws *state.WinState) *gtk.CheckButton {
 	return hideInactiveCheckbox
 }
 
+// GetManualOrderButton returns a button that switches the config view back to
+// the manual order after it has been sorted by another column.
+func GetManualOrderButton(ws *state.WinState) *gtk.Button {
+	manualOrderBtn, err := gtk.ButtonNewWithMnemonic(constants.ManualOrderBtnLabel)
+	if err != nil {
+		log.Fatal("failed to create manual order button:", err)
+	}
+
+	SetSpacerMarginsGtkBtn(manualOrderBtn)
+
+	manualOrderBtn.Connect(constants.GtkSignalClicked, func() { SetConfigSortManual(ws) })
+
+	return manualOrderBtn
+}
+
 func GetConfEditButtons(ws *state.WinState) (*gtk.Button, *gtk.Button, *gtk.Button) {
 	addConfItemBtn, err := gtk.ButtonNewWithMnemonic(constants.AddBtnLabel)
 	if err != nil {
@@ -1181,6 +1764,8 @@ func GetConfigTab(ws *state.WinState) (*gtk.ScrolledWindow, *gtk.TreeView, *gtk.
 
 			ws.SelectedConfIDs[id] = true
 		}
+
+		RefreshDetailPanel(ws)
 	}
 
 	configTreeSelection.Connect(constants.GtkSignalChanged, selectionChanged)
@@ -1243,7 +1828,7 @@ func LoadConfig(
 				return
 			}
 
-			*ws.TX, err = oldutil.LoadConfig(ws.OpenFileName)
+			conf, err := oldutil.LoadConfig(ws.OpenFileName)
 			if err != nil {
 				m := fmt.Sprintf("Failed to load config \"%v\": %v", ws.OpenFileName, err.Error())
 				d := gtk.MessageDialogNew(ws.Win, gtk.DIALOG_MODAL, gtk.MESSAGE_ERROR, gtk.BUTTONS_OK, "%s", m)
@@ -1255,13 +1840,14 @@ func LoadConfig(
 				return
 			}
 
+			SetConf(ws, conf)
+
 			extraDialogMessageText := ""
 
 			if len(*ws.TX) == 0 {
 				extraDialogMessageText = " The configuration was empty, so a sample recurring transaction has been added."
-				newTX := lib.GetNewTX(time.Now())
-				// newTX.Order = 1
-				*ws.TX = []lib.TX{newTX}
+				newTX := planner.GetNewTX(time.Now(), nil)
+				*ws.TX = []planner.TX{newTX}
 			}
 
 			SyncConfigListStore(ws)
@@ -1301,6 +1887,8 @@ func GetConfigBaseComponents(ws *state.WinState) (*gtk.Grid, *gtk.ScrolledWindow
 	configGrid.SetOrientation(gtk.ORIENTATION_VERTICAL)
 	configSw, configTreeView, configTab := GetConfigTab(ws)
 	configGrid.Attach(configSw, 0, 0, constants.FullGridWidth, 2)
+	configGrid.Attach(GetTotalsLabel(ws), 0, 2, constants.FullGridWidth, constants.ControlsGridHeight)
+	configGrid.Attach(GetDetailPanel(ws), constants.FullGridWidth, 0, 1, constants.ScrolledWindowGridHeight)
 
 	return configGrid, configSw, configTreeView, configTab
 }
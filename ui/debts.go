@@ -0,0 +1,294 @@
+package ui
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// syncDebtPlanListStore fills the list store with how each debt in the plan
+// is paid off, with a string for each of constants.DebtPlanColumns.
+func syncDebtPlanListStore(ls *gtk.ListStore, plan *planner.DebtPlan) {
+	ls.Clear()
+
+	if plan == nil {
+		return
+	}
+
+	columns := []int{}
+	for i := range constants.DebtPlanColumns {
+		columns = append(columns, i)
+	}
+
+	for i, payoff := range plan.Payoffs {
+		err := ls.Set(ls.Append(), columns, []interface{}{
+			payoff.Debt.Name,
+			lib.FormatAsCurrency(payoff.Debt.Balance),
+			strconv.FormatFloat(payoff.Debt.Rate, 'f', -1, 64) + "%",
+			plan.GetPayoffDate(i).Format(constants.DetailDateFormat),
+			strconv.Itoa(len(payoff.Payments)),
+			lib.FormatAsCurrency(payoff.Interest),
+		})
+		if err != nil {
+			log.Printf("failed to add debt plan row: %v", err.Error())
+		}
+	}
+}
+
+// ShowDebtPlanner shows a dialog that simulates paying down a list of debts
+// with a monthly budget, using the snowball and avalanche strategies (see
+// planner.GetDebtPlan). Both strategies are summarized so that they can be
+// compared, and the payoff of each debt is shown for the chosen one, which
+// can then be added as a payment transaction for each debt. The debts and
+// budget are remembered until the window is closed.
+func ShowDebtPlanner(ws *state.WinState) {
+	now := time.Now()
+
+	d, err := gtk.DialogNewWithButtons(
+		constants.DebtPlannerTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.DebtPlannerCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.DebtPlannerAdd, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create debt planner dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.DebtPlannerWidth, constants.DebtPlannerHeight)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get debt planner content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	view, err := gtk.TextViewNew()
+	if err != nil {
+		log.Printf("failed to create debt planner input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	view.SetMonospace(true)
+
+	buf, err := view.GetBuffer()
+	if err != nil {
+		log.Printf("failed to get debt planner buffer: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	buf.SetText(planner.GetDebtsText(ws.Debts))
+
+	debtsSw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create debt planner scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	debtsSw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	debtsSw.SetShadowType(gtk.SHADOW_IN)
+	debtsSw.SetSizeRequest(-1, constants.DebtPlannerDebtsHeight)
+	debtsSw.Add(view)
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create debt planner grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetRowSpacing(constants.UISpacer / 2)
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	budget, err := gtk.EntryNew()
+	if err != nil {
+		log.Printf("failed to create debt planner budget input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	budget.SetPlaceholderText(constants.DebtPlannerBudgetPlaceholder)
+	if ws.DebtBudget > 0 {
+		budget.SetText(lib.FormatAsCurrency(ws.DebtBudget))
+	}
+
+	start, err := gtk.EntryNew()
+	if err != nil {
+		log.Printf("failed to create debt planner start input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	start.SetText(lib.GetNowDateString(time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)))
+
+	strategy, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Printf("failed to create debt planner strategy picker: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	strategy.Append(constants.DebtAvalanche, constants.DebtAvalancheName)
+	strategy.Append(constants.DebtSnowball, constants.DebtSnowballName)
+	strategy.SetActiveID(constants.DebtAvalanche)
+
+	grid.Attach(newDetailLabel(constants.DebtPlannerBudget), 0, 0, 1, 1)
+	grid.Attach(budget, 1, 0, 1, 1)
+	grid.Attach(newDetailLabel(constants.DebtPlannerStart), 0, 1, 1, 1)
+	grid.Attach(start, 1, 1, 1, 1)
+	grid.Attach(newDetailLabel(constants.DebtPlannerStrategy), 0, 2, 1, 1)
+	grid.Attach(strategy, 1, 2, 1, 1)
+
+	summary := newDetailLabel("")
+
+	types := []glib.Type{}
+	for range constants.DebtPlanColumns {
+		types = append(types, glib.TYPE_STRING)
+	}
+
+	ls, err := gtk.ListStoreNew(types...)
+	if err != nil {
+		log.Printf("failed to create debt plan list store: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	tv, err := gtk.TreeViewNewWithModel(ls)
+	if err != nil {
+		log.Printf("failed to create debt plan tree view: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for i, column := range constants.DebtPlanColumns {
+		tvc, err := createColumn(column, i)
+		if err != nil {
+			log.Printf("failed to create debt plan column %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tv.AppendColumn(tvc)
+	}
+
+	planSw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create debt plan scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	planSw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	planSw.SetShadowType(gtk.SHADOW_IN)
+	planSw.SetVExpand(true)
+	planSw.Add(tv)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get debt planner add button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	var chosen *planner.DebtPlan
+
+	check := func() {
+		chosen = nil
+		defer func() {
+			applyBtn.ToWidget().SetSensitive(chosen != nil)
+			syncDebtPlanListStore(ls, chosen)
+		}()
+
+		first, last := buf.GetBounds()
+		text, err := buf.GetText(first, last, true)
+		if err != nil {
+			log.Printf("failed to get debt planner text: %v", err.Error())
+			return
+		}
+
+		debts, err := planner.ParseDebts(text)
+		if err != nil {
+			summary.SetText(err.Error())
+			return
+		}
+
+		ws.Debts = debts
+
+		b, _ := budget.GetText()
+		ws.DebtBudget = parseLoanAmount(b)
+
+		s, _ := start.GetText()
+		day, err := planner.ParseFirstPaymentDate(s)
+		if err != nil {
+			summary.SetText(err.Error())
+			return
+		}
+
+		lines := []string{}
+		for _, st := range []string{constants.DebtAvalanche, constants.DebtSnowball} {
+			plan, err := planner.GetDebtPlan(debts, ws.DebtBudget, day, st)
+			if err != nil {
+				summary.SetText(err.Error())
+				return
+			}
+
+			lines = append(lines, plan.GetSummary())
+
+			if st == strategy.GetActiveID() {
+				chosen = &plan
+			}
+		}
+
+		summary.SetText(strings.Join(lines, "\n"))
+	}
+
+	buf.Connect(constants.GtkSignalChanged, check)
+	budget.Connect(constants.GtkSignalChanged, check)
+	start.Connect(constants.GtkSignalChanged, check)
+	strategy.Connect(constants.GtkSignalChanged, check)
+
+	content.PackStart(newDetailLabel(constants.DebtPlannerHelp), false, false, 0)
+	content.PackStart(debtsSw, false, false, 0)
+	content.PackStart(grid, false, false, 0)
+	content.PackStart(summary, false, false, 0)
+	content.PackStart(planSw, true, true, 0)
+	content.ShowAll()
+
+	check()
+
+	resp := d.Run()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK || chosen == nil {
+		return
+	}
+
+	*ws.TX = append(*ws.TX, planner.GetDebtPlanTXs(*chosen, now, *ws.TX)...)
+
+	// new conf items are added at the bottom, as in AddConfItem
+	SetConfigScrollPosition(ws, 65535, -1)
+	ClearAllSelections(ws)
+	UpdateResults(ws, false)
+	SyncConfigListStore(ws)
+	RestoreConfigScrollPosition(ws)
+}
@@ -0,0 +1,784 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+const (
+	detailStackEmpty = "empty"
+	detailStackForm  = "form"
+)
+
+// detailPanel holds the widgets of the transaction detail panel, which shows
+// the selected TX as a form. Every edit made in the form is applied through
+// ConfigChangeByID, just like edits made in the config tree view.
+type detailPanel struct {
+	ws *state.WinState
+
+	// id is the ID of the TX currently shown in the form
+	id string
+	// updating is true while the form is being populated, so that the
+	// resulting change signals aren't mistaken for user edits
+	updating bool
+
+	stack       *gtk.Stack
+	name        *gtk.Entry
+	amount      *gtk.Entry
+	amounts     *gtk.Entry
+	seasonal    *gtk.Entry
+	link        *gtk.Entry
+	active      *gtk.CheckButton
+	frequency   *gtk.ComboBoxText
+	interval    *gtk.SpinButton
+	monthlyNth  *gtk.ComboBoxText // "" for the same date, otherwise MonthlyRule.Nth
+	monthlyDay  *gtk.ComboBoxText // MonthlyRule.Day
+	monthlyBox  *gtk.Box
+	weekdayBox  *gtk.Box
+	rrule       *gtk.Entry
+	shift       *gtk.ComboBoxText // "" for none, otherwise TX.Shift
+	weekdays    []*gtk.ToggleButton
+	starts      *gtk.Entry
+	ends        *gtk.Entry
+	exceptions  *gtk.Entry
+	note        *gtk.TextBuffer
+	txID        *gtk.Label
+	createdAt   *gtk.Label
+	updatedAt   *gtk.Label
+	occurrences *gtk.Label
+}
+
+// RefreshDetailPanel repopulates the detail panel from the currently selected
+// TX. It's safe to call before the panel has been built.
+func RefreshDetailPanel(ws *state.WinState) {
+	if ws.RefreshDetail != nil {
+		ws.RefreshDetail()
+	}
+}
+
+// getTX returns the TX currently shown in the form, or nil if there isn't one.
+func (d *detailPanel) getTX() *planner.TX {
+	if d.id == "" {
+		return nil
+	}
+
+	i, err := planner.GetTXByID(d.ws.TX, d.id)
+	if err != nil {
+		return nil
+	}
+
+	return &(*d.ws.TX)[i]
+}
+
+// change applies an edit made in the form to the TX it shows.
+func (d *detailPanel) change(column int, newValue interface{}) {
+	if d.updating || d.id == "" {
+		return
+	}
+
+	ConfigChangeByID(d.ws, d.id, column, newValue)
+}
+
+// refresh shows the selected TX in the form, or a hint if there isn't exactly
+// one selected TX.
+func (d *detailPanel) refresh() {
+	d.id = ""
+
+	selected := []string{}
+	for id, sel := range d.ws.SelectedConfIDs {
+		if sel {
+			selected = append(selected, id)
+		}
+	}
+
+	if len(selected) == 1 {
+		d.id = selected[0]
+	}
+
+	tx := d.getTX()
+	if tx == nil {
+		d.id = ""
+		d.stack.SetVisibleChildName(detailStackEmpty)
+		return
+	}
+
+	d.updating = true
+	defer func() { d.updating = false }()
+
+	d.name.SetText(tx.Name)
+	d.amount.SetText(planner.GetAmountInputText(tx))
+	d.seasonal.SetText(planner.GetSeasonalAmountsText(tx))
+	d.link.SetText(planner.GetLinkText(tx))
+	d.amounts.SetText(planner.GetAmountScheduleText(tx))
+	d.active.SetActive(tx.Active)
+
+	if !d.frequency.SetActiveID(tx.Frequency) {
+		d.frequency.SetActive(-1)
+	}
+
+	d.interval.SetValue(float64(tx.Interval))
+
+	if r := tx.GetMonthlyRule(); r != nil {
+		d.monthlyNth.SetActiveID(strconv.Itoa(r.Nth))
+		d.monthlyDay.SetActiveID(r.Day)
+	} else {
+		d.monthlyNth.SetActiveID("")
+		d.monthlyDay.SetActive(-1)
+	}
+
+	d.monthlyDay.SetSensitive(tx.MonthlyRule != "")
+
+	// a recurrence rule overrides the rest of the recurrence fields
+	simple := tx.RRule == ""
+	d.frequency.SetSensitive(simple)
+	d.interval.SetSensitive(simple)
+	d.weekdayBox.SetSensitive(simple && tx.Frequency != constants.DAILY)
+	d.monthlyBox.SetSensitive(simple && tx.Frequency == constants.MONTHLY)
+	d.rrule.SetText(GetRRuleText(tx.RRule))
+
+	if !d.shift.SetActiveID(tx.Shift) {
+		d.shift.SetActive(-1)
+	}
+
+	for i, btn := range d.weekdays {
+		btn.SetActive(tx.Weekdays[i])
+	}
+
+	d.starts.SetText(tx.GetStartDateString())
+	d.ends.SetText(tx.GetEndsDateString())
+	d.exceptions.SetText(planner.GetExceptionsText(tx.Exceptions))
+	d.note.SetText(tx.Note)
+	d.txID.SetText(tx.ID)
+	d.createdAt.SetText(tx.CreatedAt.Local().Format(constants.DetailTimestampFormat))
+	d.updatedAt.SetText(tx.UpdatedAt.Local().Format(constants.DetailTimestampFormat))
+	d.occurrences.SetText(d.getOccurrencesText(tx))
+
+	d.stack.SetVisibleChildName(detailStackForm)
+}
+
+// getOccurrencesText lists the next few dates that the TX recurs on within the
+// projection window, one per line.
+func (d *detailPanel) getOccurrencesText(tx *planner.TX) string {
+	now := time.Now()
+	start := lib.GetDateFromStrSafe(d.ws.StartDate, now)
+	end := lib.GetDateFromStrSafe(d.ws.EndDate, now)
+
+	occurrences, err := planner.GetNextOccurrences(tx, start, end, constants.DetailOccurrencesCount, d.ws.Holidays)
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(occurrences) == 0 {
+		return constants.DetailNoOccurrences
+	}
+
+	lines := []string{}
+	for _, o := range occurrences {
+		line := o.Date.Format(constants.DetailDateFormat)
+		if note := planner.GetOccurrenceNote(tx, o); note != "" {
+			line = fmt.Sprintf(constants.OccurrenceNoteFormat, line, note)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if !tx.Active {
+		lines = append(lines, constants.DetailInactiveOccurrences)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// changeMonthlyRule applies the monthly rule that's chosen in the form's two
+// monthly rule pickers.
+func (d *detailPanel) changeMonthlyRule() {
+	tx := d.getTX()
+	if tx == nil || d.updating {
+		return
+	}
+
+	rule := ""
+
+	if nth, err := strconv.Atoi(d.monthlyNth.GetActiveID()); err == nil {
+		day := d.monthlyDay.GetActiveID()
+		if day == "" {
+			day = constants.MonthlyRuleDay
+		}
+
+		rule = planner.MonthlyRule{Nth: nth, Day: day}.String()
+	}
+
+	if rule != tx.MonthlyRule {
+		d.change(constants.COLUMN_MONTHLYRULE, rule)
+	}
+}
+
+// connectEntry commits the entry's text when the user presses enter or leaves
+// the entry, but only if the text differs from the TX's current value, so that
+// e.g. tabbing through the form doesn't reformat anything.
+func (d *detailPanel) connectEntry(entry *gtk.Entry, column int, current func(tx *planner.TX) string) {
+	commit := func() {
+		tx := d.getTX()
+		if tx == nil || d.updating {
+			return
+		}
+
+		text, err := entry.GetText()
+		if err != nil {
+			log.Printf("failed to get detail panel entry text: %v", err.Error())
+			return
+		}
+
+		if text == current(tx) {
+			return
+		}
+
+		d.change(column, text)
+	}
+
+	entry.Connect(constants.GtkSignalActivate, commit)
+	entry.Connect(constants.GtkSignalFocusOut, func() bool {
+		commit()
+		return false
+	})
+}
+
+// newDatePicker creates a YYYY-MM-DD entry with a button that shows a calendar
+// for picking the date instead of typing it.
+func (d *detailPanel) newDatePicker(column int, current func(tx *planner.TX) string) (*gtk.Box, *gtk.Entry) {
+	box, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create date picker box: %v", err.Error())
+	}
+
+	if ctx, err := box.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	entry, err := gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create date picker entry: %v", err.Error())
+	}
+
+	entry.SetHExpand(true)
+	entry.SetPlaceholderText(constants.DetailDatePlaceholder)
+	d.connectEntry(entry, column, current)
+
+	cal, err := gtk.CalendarNew()
+	if err != nil {
+		log.Fatalf("failed to create date picker calendar: %v", err.Error())
+	}
+
+	cal.SetTooltipText(constants.DetailDatePickerTooltip)
+
+	btn, err := gtk.MenuButtonNew()
+	if err != nil {
+		log.Fatalf("failed to create date picker button: %v", err.Error())
+	}
+
+	popover, err := gtk.PopoverNew(btn)
+	if err != nil {
+		log.Fatalf("failed to create date picker popover: %v", err.Error())
+	}
+
+	popover.Add(cal)
+	cal.Show()
+	btn.SetPopover(popover)
+
+	// start the calendar at the entry's date every time it's shown
+	btn.Connect(constants.GtkSignalToggled, func(b *gtk.MenuButton) {
+		if !b.GetActive() {
+			return
+		}
+
+		text, _ := entry.GetText()
+		yr, mo, day := lib.ParseYearMonthDateString(strings.TrimSpace(text))
+		if yr == 0 || mo == 0 || day == 0 {
+			now := time.Now()
+			yr, mo, day = now.Year(), int(now.Month()), now.Day()
+		}
+
+		cal.SelectMonth(uint(mo-1), uint(yr))
+		cal.SelectDay(uint(day))
+	})
+
+	cal.Connect(constants.GtkSignalDayDoubleClick, func(c *gtk.Calendar) {
+		yr, mo, day := c.GetDate()
+		text := lib.GetDateString(int(yr), int(mo)+1, int(day))
+		entry.SetText(text)
+		popover.Popdown()
+
+		tx := d.getTX()
+		if tx == nil || text == current(tx) {
+			return
+		}
+
+		d.change(column, text)
+	})
+
+	box.PackStart(entry, true, true, 0)
+	box.PackStart(btn, false, false, 0)
+
+	return box, entry
+}
+
+func newDetailLabel(text string) *gtk.Label {
+	l, err := gtk.LabelNew(text)
+	if err != nil {
+		log.Fatalf("failed to create detail panel label: %v", err.Error())
+	}
+
+	l.SetXAlign(0)
+	l.SetLineWrap(true)
+
+	return l
+}
+
+// GetDetailPanel builds the transaction detail panel, which is collapsed or
+// expanded with the button from GetDetailPanelButton.
+func GetDetailPanel(ws *state.WinState) *gtk.Revealer {
+	var err error
+
+	d := &detailPanel{ws: ws}
+
+	form, err := gtk.GridNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel grid: %v", err.Error())
+	}
+
+	form.SetRowSpacing(constants.UISpacer / 2)
+	form.SetColumnSpacing(constants.UISpacer)
+	form.SetMarginStart(constants.UISpacer)
+	form.SetMarginEnd(constants.UISpacer)
+	form.SetMarginTop(constants.UISpacer)
+	form.SetMarginBottom(constants.UISpacer)
+
+	row := 0
+	addRow := func(label string, w gtk.IWidget) {
+		form.Attach(newDetailLabel(label), 0, row, 1, 1)
+		form.Attach(w, 1, row, 1, 1)
+		row++
+	}
+
+	d.name, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel name entry: %v", err.Error())
+	}
+
+	d.name.SetHExpand(true)
+	d.connectEntry(d.name, constants.COLUMN_NAME, func(tx *planner.TX) string { return tx.Name })
+	addRow(constants.DetailLabelName, d.name)
+
+	d.amount, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel amount entry: %v", err.Error())
+	}
+
+	d.amount.SetTooltipText(constants.DetailAmountTooltip)
+	d.connectEntry(d.amount, constants.COLUMN_AMOUNT, planner.GetAmountInputText)
+	addRow(constants.DetailLabelAmount, d.amount)
+
+	seasonalBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create detail panel seasonal amounts box: %v", err.Error())
+	}
+
+	if ctx, err := seasonalBox.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	d.seasonal, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel seasonal amounts entry: %v", err.Error())
+	}
+
+	d.seasonal.SetHExpand(true)
+	d.seasonal.SetPlaceholderText(constants.DetailSeasonalPlaceholder)
+	d.connectEntry(d.seasonal, constants.COLUMN_SEASONAL, planner.GetSeasonalAmountsText)
+
+	seasonalEditBtn, err := gtk.ButtonNewWithMnemonic(constants.DetailSeasonalEditLabel)
+	if err != nil {
+		log.Fatalf("failed to create detail panel seasonal amounts edit button: %v", err.Error())
+	}
+
+	seasonalEditBtn.Connect(constants.GtkSignalClicked, func() {
+		if d.id != "" {
+			ShowSeasonalAmountsEditor(d.ws, d.id)
+		}
+	})
+
+	seasonalBox.PackStart(d.seasonal, true, true, 0)
+	seasonalBox.PackStart(seasonalEditBtn, false, false, 0)
+	addRow(constants.DetailLabelSeasonal, seasonalBox)
+
+	d.link, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel linked amount entry: %v", err.Error())
+	}
+
+	d.link.SetPlaceholderText(constants.DetailLinkPlaceholder)
+	d.link.SetTooltipText(constants.DetailLinkTooltip)
+	d.connectEntry(d.link, constants.COLUMN_LINK, planner.GetLinkText)
+	addRow(constants.DetailLabelLink, d.link)
+
+	d.amounts, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel amount changes entry: %v", err.Error())
+	}
+
+	d.amounts.SetPlaceholderText(constants.DetailAmountsPlaceholder)
+	d.amounts.SetTooltipText(constants.DetailAmountsTooltip)
+	d.connectEntry(d.amounts, constants.COLUMN_AMOUNTS, planner.GetAmountScheduleText)
+	addRow(constants.DetailLabelAmounts, d.amounts)
+
+	d.active, err = gtk.CheckButtonNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel active checkbox: %v", err.Error())
+	}
+
+	d.active.Connect(constants.GtkSignalToggled, func(c *gtk.CheckButton) {
+		d.change(constants.COLUMN_ACTIVE, c.GetActive())
+	})
+	addRow(constants.DetailLabelActive, d.active)
+
+	d.frequency, err = gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel frequency picker: %v", err.Error())
+	}
+
+	for _, f := range constants.Frequencies {
+		d.frequency.Append(f, f)
+	}
+
+	d.frequency.Connect(constants.GtkSignalChanged, func(c *gtk.ComboBoxText) {
+		f := c.GetActiveID()
+		if f == "" {
+			return
+		}
+
+		if tx := d.getTX(); tx != nil && tx.Frequency == f {
+			return
+		}
+
+		d.change(constants.COLUMN_FREQUENCY, f)
+	})
+	addRow(constants.DetailLabelFrequency, d.frequency)
+
+	d.interval, err = gtk.SpinButtonNewWithRange(1, 9999, 1)
+	if err != nil {
+		log.Fatalf("failed to create detail panel interval input: %v", err.Error())
+	}
+
+	d.interval.Connect(constants.GtkSignalValueChanged, func(s *gtk.SpinButton) {
+		if tx := d.getTX(); tx != nil && tx.Interval == s.GetValueAsInt() {
+			return
+		}
+
+		d.change(constants.COLUMN_INTERVAL, strconv.Itoa(s.GetValueAsInt()))
+	})
+	addRow(constants.DetailLabelInterval, d.interval)
+
+	d.monthlyBox, err = gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create detail panel monthly rule box: %v", err.Error())
+	}
+
+	if ctx, err := d.monthlyBox.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	d.monthlyBox.SetTooltipText(constants.DetailMonthlyRuleTooltip)
+
+	d.monthlyNth, err = gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel monthly rule picker: %v", err.Error())
+	}
+
+	d.monthlyNth.Append("", constants.DetailMonthlyRuleSameDate)
+	for i := range constants.MonthlyRuleOrdinals {
+		d.monthlyNth.Append(strconv.Itoa(i+1), planner.GetMonthlyRuleOrdinal(i+1))
+	}
+	d.monthlyNth.Append("-1", planner.GetMonthlyRuleOrdinal(-1))
+
+	d.monthlyDay, err = gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel monthly rule day picker: %v", err.Error())
+	}
+
+	d.monthlyDay.Append(constants.MonthlyRuleDay, constants.MonthlyRuleDay)
+	d.monthlyDay.Append(constants.MonthlyRuleBusinessDay, constants.MonthlyRuleBusinessDay)
+	for _, weekday := range constants.Weekdays {
+		d.monthlyDay.Append(weekday, weekday)
+	}
+
+	d.monthlyNth.Connect(constants.GtkSignalChanged, d.changeMonthlyRule)
+	d.monthlyDay.Connect(constants.GtkSignalChanged, d.changeMonthlyRule)
+	d.monthlyBox.PackStart(d.monthlyNth, true, true, 0)
+	d.monthlyBox.PackStart(d.monthlyDay, true, true, 0)
+	addRow(constants.DetailLabelMonthlyRule, d.monthlyBox)
+
+	weekdaysBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create detail panel weekdays box: %v", err.Error())
+	}
+
+	if ctx, err := weekdaysBox.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	for i, weekday := range constants.Weekdays {
+		btn, err := gtk.ToggleButtonNewWithLabel(weekday[:2])
+		if err != nil {
+			log.Fatalf("failed to create detail panel %v toggle: %v", weekday, err.Error())
+		}
+
+		btn.SetTooltipText(weekday)
+		column := constants.COLUMN_MONDAY + i
+		btn.Connect(constants.GtkSignalToggled, func(b *gtk.ToggleButton) {
+			d.change(column, b.GetActive())
+		})
+
+		weekdaysBox.PackStart(btn, true, true, 0)
+		d.weekdays = append(d.weekdays, btn)
+	}
+
+	addRow(constants.DetailLabelWeekdays, weekdaysBox)
+	d.weekdayBox = weekdaysBox
+
+	rruleBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create detail panel rrule box: %v", err.Error())
+	}
+
+	if ctx, err := rruleBox.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	d.rrule, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel rrule entry: %v", err.Error())
+	}
+
+	d.rrule.SetHExpand(true)
+	d.rrule.SetPlaceholderText(constants.DetailRRulePlaceholder)
+	d.connectEntry(d.rrule, constants.COLUMN_RRULE, func(tx *planner.TX) string { return GetRRuleText(tx.RRule) })
+
+	rruleEditBtn, err := gtk.ButtonNewWithMnemonic(constants.DetailRRuleEditLabel)
+	if err != nil {
+		log.Fatalf("failed to create detail panel rrule edit button: %v", err.Error())
+	}
+
+	rruleEditBtn.Connect(constants.GtkSignalClicked, func() {
+		if d.id != "" {
+			ShowRRuleEditor(d.ws, d.id)
+		}
+	})
+
+	rruleBox.PackStart(d.rrule, true, true, 0)
+	rruleBox.PackStart(rruleEditBtn, false, false, 0)
+	addRow(constants.DetailLabelRRule, rruleBox)
+
+	d.shift, err = gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel weekend/holiday picker: %v", err.Error())
+	}
+
+	for _, shift := range constants.Shifts {
+		text := planner.GetShiftText(shift)
+		if shift == "" {
+			text = constants.DetailShiftNone
+		}
+
+		d.shift.Append(shift, text)
+	}
+
+	d.shift.SetTooltipText(constants.DetailShiftTooltip)
+	d.shift.Connect(constants.GtkSignalChanged, func(c *gtk.ComboBoxText) {
+		if tx := d.getTX(); tx == nil || tx.Shift == c.GetActiveID() {
+			return
+		}
+
+		d.change(constants.COLUMN_SHIFT, c.GetActiveID())
+	})
+	addRow(constants.DetailLabelShift, d.shift)
+
+	startsBox, startsEntry := d.newDatePicker(constants.COLUMN_STARTS, func(tx *planner.TX) string { return tx.GetStartDateString() })
+	d.starts = startsEntry
+	addRow(constants.DetailLabelStarts, startsBox)
+
+	endsBox, endsEntry := d.newDatePicker(constants.COLUMN_ENDS, func(tx *planner.TX) string { return tx.GetEndsDateString() })
+	d.ends = endsEntry
+	addRow(constants.DetailLabelEnds, endsBox)
+
+	exceptionsBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+	if err != nil {
+		log.Fatalf("failed to create detail panel exceptions box: %v", err.Error())
+	}
+
+	if ctx, err := exceptionsBox.GetStyleContext(); err == nil {
+		ctx.AddClass(constants.GtkStyleClassLinked)
+	}
+
+	d.exceptions, err = gtk.EntryNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel exceptions entry: %v", err.Error())
+	}
+
+	d.exceptions.SetHExpand(true)
+	d.exceptions.SetPlaceholderText(constants.None)
+	d.exceptions.SetTooltipText(constants.DetailExceptionsTooltip)
+	d.connectEntry(d.exceptions, constants.COLUMN_EXCEPTIONS, func(tx *planner.TX) string { return planner.GetExceptionsText(tx.Exceptions) })
+
+	exceptionsAddBtn, err := gtk.ButtonNewWithMnemonic(constants.DetailExceptionsAdd)
+	if err != nil {
+		log.Fatalf("failed to create detail panel exceptions add button: %v", err.Error())
+	}
+
+	exceptionsAddBtn.Connect(constants.GtkSignalClicked, func() {
+		if d.id != "" {
+			ShowExceptionEditor(d.ws, d.id, time.Time{})
+		}
+	})
+
+	exceptionsBox.PackStart(d.exceptions, true, true, 0)
+	exceptionsBox.PackStart(exceptionsAddBtn, false, false, 0)
+	addRow(constants.DetailLabelExceptions, exceptionsBox)
+
+	noteView, err := gtk.TextViewNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel note input: %v", err.Error())
+	}
+
+	noteView.SetWrapMode(gtk.WRAP_WORD_CHAR)
+
+	d.note, err = noteView.GetBuffer()
+	if err != nil {
+		log.Fatalf("failed to get detail panel note buffer: %v", err.Error())
+	}
+
+	noteView.Connect(constants.GtkSignalFocusOut, func() bool {
+		tx := d.getTX()
+		if tx == nil {
+			return false
+		}
+
+		start, end := d.note.GetBounds()
+		text, err := d.note.GetText(start, end, true)
+		if err != nil {
+			log.Printf("failed to get detail panel note text: %v", err.Error())
+			return false
+		}
+
+		if text != tx.Note {
+			d.change(constants.COLUMN_NOTE, text)
+		}
+
+		return false
+	})
+
+	noteSw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Fatalf("failed to create detail panel note scrolled window: %v", err.Error())
+	}
+
+	noteSw.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	noteSw.SetShadowType(gtk.SHADOW_IN)
+	noteSw.SetSizeRequest(-1, constants.DetailNoteHeight)
+	noteSw.Add(noteView)
+	addRow(constants.DetailLabelNote, noteSw)
+
+	d.txID = newDetailLabel("")
+	d.txID.SetSelectable(true)
+	addRow(constants.DetailLabelID, d.txID)
+
+	d.createdAt = newDetailLabel("")
+	addRow(constants.DetailLabelCreatedAt, d.createdAt)
+
+	d.updatedAt = newDetailLabel("")
+	addRow(constants.DetailLabelUpdatedAt, d.updatedAt)
+
+	d.occurrences = newDetailLabel("")
+	d.occurrences.SetSelectable(true)
+	d.occurrences.SetYAlign(0)
+	addRow(constants.DetailLabelOccurrences, d.occurrences)
+
+	empty := newDetailLabel(constants.DetailNothingSelected)
+	empty.SetXAlign(0.5)
+	empty.SetJustify(gtk.JUSTIFY_CENTER)
+
+	d.stack, err = gtk.StackNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel stack: %v", err.Error())
+	}
+
+	// a stack only switches to children that are already visible
+	empty.Show()
+	form.ShowAll()
+	d.stack.AddNamed(empty, detailStackEmpty)
+	d.stack.AddNamed(form, detailStackForm)
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Fatalf("failed to create detail panel scrolled window: %v", err.Error())
+	}
+
+	sw.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	sw.SetSizeRequest(constants.DetailPanelWidth, -1)
+	sw.SetVExpand(true)
+	sw.Add(d.stack)
+
+	revealer, err := gtk.RevealerNew()
+	if err != nil {
+		log.Fatalf("failed to create detail panel revealer: %v", err.Error())
+	}
+
+	revealer.SetTransitionType(gtk.REVEALER_TRANSITION_TYPE_SLIDE_LEFT)
+	revealer.Add(sw)
+	revealer.SetRevealChild(ws.Prefs.ShowDetailPanel)
+
+	ws.DetailRevealer = revealer
+	ws.RefreshDetail = d.refresh
+
+	d.refresh()
+
+	return revealer
+}
+
+// GetDetailPanelButton returns a toggle button that expands and collapses the
+// detail panel. Whether it's expanded is remembered across windows.
+func GetDetailPanelButton(ws *state.WinState) *gtk.ToggleButton {
+	btn, err := gtk.ToggleButtonNewWithMnemonic(constants.DetailBtnLabel)
+	if err != nil {
+		log.Fatalf("failed to create details button: %v", err.Error())
+	}
+
+	SetSpacerMarginsGtkBtn(&btn.Button)
+
+	btn.SetActive(ws.Prefs.ShowDetailPanel)
+	btn.Connect(constants.GtkSignalToggled, func(b *gtk.ToggleButton) {
+		show := b.GetActive()
+		ws.DetailRevealer.SetRevealChild(show)
+
+		if ws.Prefs.ShowDetailPanel != show {
+			ws.Prefs.ShowDetailPanel = show
+			SavePrefs(ws)
+		}
+
+		if show {
+			RefreshDetailPanel(ws)
+		}
+	})
+
+	return btn
+}
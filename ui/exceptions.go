@@ -0,0 +1,388 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// setOccurrenceException adds (or replaces) an exception on the TX with the
+// provided ID. An exception that doesn't change anything removes the existing
+// exception for its date instead.
+func setOccurrenceException(ws *state.WinState, id string, e planner.Exception) {
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("failed to find tx %v to change its exceptions: %v", id, err.Error())
+		return
+	}
+
+	exceptions := (*ws.TX)[i].SetException(e)
+	ConfigChangeByID(ws, id, constants.COLUMN_EXCEPTIONS, planner.GetExceptionsText(exceptions))
+}
+
+// getExceptionEditorText builds the text form of an exception (see
+// planner.ParseException) from the fields of the exception editor.
+func getExceptionEditorText(date string, skip bool, amount, moveTo string) string {
+	parts := []string{strings.TrimSpace(date)}
+
+	if skip {
+		parts = append(parts, constants.ExceptionSkip)
+	}
+
+	if a := strings.Join(strings.Fields(amount), ""); a != "" {
+		parts = append(parts, a)
+	}
+
+	if to := strings.TrimSpace(moveTo); to != "" {
+		parts = append(parts, constants.ExceptionMoveTo, to)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ShowExceptionEditor shows a dialog for skipping, changing the amount of, or
+// moving the occurrence of the TX with the provided ID that's scheduled on
+// day. If day is zero, the user picks the day instead. If there's already an
+// exception for the day, it's shown, and it can be removed.
+func ShowExceptionEditor(ws *state.WinState, id string, day time.Time) {
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("failed to find tx %v to edit its exceptions: %v", id, err.Error())
+		return
+	}
+
+	tx := (*ws.TX)[i]
+
+	d, err := gtk.DialogNewWithButtons(
+		fmt.Sprintf("%v - %v", constants.ExceptionEditorTitle, tx.Name),
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.ExceptionEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.ExceptionEditorRemove, gtk.RESPONSE_REJECT},
+		[]interface{}{constants.ExceptionEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create exception editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.ExceptionEditorWidth, -1)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get exception editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	form, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create exception editor grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	form.SetRowSpacing(constants.UISpacer / 2)
+	form.SetColumnSpacing(constants.UISpacer)
+	form.SetMarginStart(constants.UISpacer)
+	form.SetMarginEnd(constants.UISpacer)
+	form.SetMarginTop(constants.UISpacer)
+	form.SetMarginBottom(constants.UISpacer)
+
+	row := 0
+	addRow := func(label string, w gtk.IWidget) {
+		form.Attach(newDetailLabel(label), 0, row, 1, 1)
+		form.Attach(w, 1, row, 1, 1)
+		row++
+	}
+
+	newEntry := func(placeholder string) *gtk.Entry {
+		e, err := gtk.EntryNew()
+		if err != nil {
+			log.Fatalf("failed to create exception editor entry: %v", err.Error())
+		}
+
+		e.SetHExpand(true)
+		e.SetPlaceholderText(placeholder)
+
+		return e
+	}
+
+	date := newEntry(constants.DetailDatePlaceholder)
+	addRow(constants.ExceptionEditorDate, date)
+
+	skip, err := gtk.CheckButtonNewWithLabel(constants.ExceptionEditorSkip)
+	if err != nil {
+		log.Fatalf("failed to create exception editor skip checkbox: %v", err.Error())
+	}
+
+	addRow("", skip)
+
+	amount := newEntry(fmt.Sprintf(constants.ExceptionEditorUnchanged, planner.FormatExceptionAmount(tx.Amount)))
+	addRow(constants.ExceptionEditorAmount, amount)
+
+	moveTo := newEntry(fmt.Sprintf(constants.ExceptionEditorUnchanged, constants.DetailDatePlaceholder))
+	addRow(constants.ExceptionEditorMoveTo, moveTo)
+
+	status := newDetailLabel("")
+	form.Attach(status, 0, row, 2, 1)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get exception editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	removeBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_REJECT)
+	if err != nil {
+		log.Printf("failed to get exception editor remove button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getText := func() string {
+		dateText, _ := date.GetText()
+		amountText, _ := amount.GetText()
+		moveToText, _ := moveTo.GetText()
+
+		return getExceptionEditorText(dateText, skip.GetActive(), amountText, moveToText)
+	}
+
+	// existing is the exception for the entered date, if there is one
+	var existing *planner.Exception
+
+	check := func() {
+		_, err := planner.ParseException(getText())
+		if err != nil {
+			status.SetText(err.Error())
+		} else {
+			status.SetText("")
+		}
+
+		applyBtn.ToWidget().SetSensitive(err == nil)
+		removeBtn.ToWidget().SetSensitive(existing != nil)
+		amount.SetSensitive(!skip.GetActive())
+		moveTo.SetSensitive(!skip.GetActive())
+	}
+
+	// show the existing exception for a date when it's entered
+	loadExisting := func() {
+		text, _ := date.GetText()
+		existing = nil
+
+		if day := (&planner.Exception{Date: strings.TrimSpace(text)}).GetDate(); !day.IsZero() {
+			existing = tx.GetException(day)
+		}
+
+		if existing == nil {
+			return
+		}
+
+		skip.SetActive(existing.Skip)
+		amount.SetText("")
+		moveTo.SetText(existing.MoveTo)
+
+		if existing.Amount != nil {
+			amount.SetText(planner.FormatExceptionAmount(*existing.Amount))
+		}
+	}
+
+	if !day.IsZero() {
+		date.SetText(lib.GetNowDateString(day))
+		loadExisting()
+	}
+
+	date.Connect(constants.GtkSignalChanged, func() {
+		loadExisting()
+		check()
+	})
+	skip.Connect(constants.GtkSignalToggled, check)
+	amount.Connect(constants.GtkSignalChanged, check)
+	moveTo.Connect(constants.GtkSignalChanged, check)
+	check()
+
+	content.PackStart(newDetailLabel(constants.ExceptionEditorHelp), false, false, 0)
+	content.PackStart(form, true, true, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	text := getText()
+	removed := existing
+	d.Destroy()
+
+	switch resp {
+	case gtk.RESPONSE_OK:
+		e, err := planner.ParseException(text)
+		if err != nil {
+			log.Printf("failed to parse exception %v: %v", text, err.Error())
+			return
+		}
+
+		setOccurrenceException(ws, id, e)
+	case gtk.RESPONSE_REJECT:
+		if removed != nil {
+			setOccurrenceException(ws, id, planner.Exception{Date: removed.Date})
+		}
+	}
+}
+
+// getResultsRowDate returns the date of the results row at the provided
+// position (relative to the results tree view's rows), or false if there
+// isn't a row there.
+func getResultsRowDate(ws *state.WinState, x, y float64) (time.Time, bool) {
+	path, _, _, _, ok := ws.ResultsTreeView.GetPathAtPos(int(x), int(y))
+	if !ok || path == nil {
+		return time.Time{}, false
+	}
+
+	iter, err := ws.ResultsListStore.GetIter(path)
+	if err != nil {
+		log.Printf("failed to get results row iter: %v", err.Error())
+		return time.Time{}, false
+	}
+
+	value, err := ws.ResultsListStore.GetValue(iter, constants.ColumnDateIndex)
+	if err != nil {
+		log.Printf("failed to get results row date: %v", err.Error())
+		return time.Time{}, false
+	}
+
+	s, err := value.GetString()
+	if err != nil {
+		log.Printf("failed to get results row date string: %v", err.Error())
+		return time.Time{}, false
+	}
+
+	yr, mo, day, err := planner.ParseDate(s)
+	if err != nil {
+		log.Printf("failed to parse results row date: %v", err.Error())
+		return time.Time{}, false
+	}
+
+	return time.Date(yr, time.Month(mo), day, 0, 0, 0, 0, time.UTC), true
+}
+
+// getOccurrenceMenu builds the submenu for a single occurrence in the results
+// row menu, which allows it to be skipped or changed.
+func getOccurrenceMenu(ws *state.WinState, o planner.DayOccurrence) (*gtk.Menu, error) {
+	menu, err := gtk.MenuNew()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create occurrence menu: %v", err.Error())
+	}
+
+	id := o.TX.ID
+	scheduled := o.Occurrence.Scheduled
+
+	add := func(label string, activate func()) {
+		item, err := gtk.MenuItemNewWithLabel(label)
+		if err != nil {
+			log.Printf("failed to create occurrence menu item %v: %v", label, err.Error())
+			return
+		}
+
+		item.Connect(constants.GtkSignalActivate, activate)
+		menu.Append(item)
+	}
+
+	if o.Skipped {
+		add(constants.ResultsMenuRestore, func() {
+			setOccurrenceException(ws, id, planner.Exception{Date: lib.GetNowDateString(scheduled)})
+		})
+
+		return menu, nil
+	}
+
+	add(constants.ResultsMenuSkip, func() {
+		setOccurrenceException(ws, id, planner.Exception{Date: lib.GetNowDateString(scheduled), Skip: true})
+	})
+	add(constants.ResultsMenuEdit, func() { ShowExceptionEditor(ws, id, scheduled) })
+
+	if o.Occurrence.Exception != nil {
+		add(constants.ResultsMenuUndo, func() {
+			setOccurrenceException(ws, id, planner.Exception{Date: lib.GetNowDateString(scheduled)})
+		})
+	}
+
+	return menu, nil
+}
+
+// SetupResultsRowMenu makes right-clicking a day in the results show a menu
+// of the transactions that occur on that day, each of which can be skipped
+// or changed for that one occurrence.
+func SetupResultsRowMenu(ws *state.WinState) {
+	// the menu is kept here so that it isn't garbage collected while shown
+	var menu *gtk.Menu
+
+	ws.ResultsTreeView.Connect(constants.GtkSignalButtonPressEvent, func(_ *gtk.TreeView, ev *gdk.Event) bool {
+		btn := gdk.EventButtonNewFromEvent(ev)
+		if btn.Button() != gdk.BUTTON_SECONDARY {
+			return false
+		}
+
+		day, ok := getResultsRowDate(ws, btn.X(), btn.Y())
+		if !ok {
+			return false
+		}
+
+		var err error
+
+		menu, err = gtk.MenuNew()
+		if err != nil {
+			log.Printf("failed to create results row menu: %v", err.Error())
+			return false
+		}
+
+		start := lib.GetDateFromStrSafe(ws.StartDate, time.Now())
+		occurrences := planner.GetDayOccurrences(*ws.TX, start, day, ws.Holidays)
+		if len(occurrences) == 0 {
+			item, err := gtk.MenuItemNewWithLabel(fmt.Sprintf(constants.ResultsMenuNothing, day.Format(constants.DetailDateFormat)))
+			if err != nil {
+				log.Printf("failed to create results row menu item: %v", err.Error())
+				return false
+			}
+
+			item.SetSensitive(false)
+			menu.Append(item)
+		}
+
+		for _, o := range occurrences {
+			label := o.TX.Name
+			if o.Skipped {
+				label = fmt.Sprintf(constants.OccurrenceNoteFormat, label, constants.ResultsMenuSkipped)
+			} else if note := planner.GetOccurrenceNote(o.TX, o.Occurrence); note != "" {
+				label = fmt.Sprintf(constants.OccurrenceNoteFormat, label, note)
+			}
+
+			item, err := gtk.MenuItemNewWithLabel(label)
+			if err != nil {
+				log.Printf("failed to create results row menu item %v: %v", label, err.Error())
+				continue
+			}
+
+			submenu, err := getOccurrenceMenu(ws, o)
+			if err != nil {
+				log.Printf("failed to create results row submenu %v: %v", label, err.Error())
+				continue
+			}
+
+			item.SetSubmenu(submenu)
+			menu.Append(item)
+		}
+
+		menu.ShowAll()
+		menu.PopupAtPointer(ev)
+
+		return true
+	})
+}
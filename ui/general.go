@@ -8,6 +8,7 @@ import (
 
 	c "github.com/charles-m-knox/gtk-finance-planner/constants"
 	"github.com/charles-m-knox/gtk-finance-planner/oldutil"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
 	"github.com/charles-m-knox/gtk-finance-planner/state"
 
 	lib "github.com/charles-m-knox/finance-planner-lib"
@@ -35,39 +36,75 @@ func createCheckboxColumn(title string, columnID int, radio bool, listStore *gtk
 		return tvc, fmt.Errorf("unable to create checkbox cell column: %v", err.Error())
 	}
 	column.SetResizable(true)
+	addProblemHighlight(column, &cellRenderer.CellRenderer, columnID)
 	column.SetClickable(true)
 	column.SetVisible(true)
 
 	return column, nil
 }
 
-func ProcessInitialConfigLoad(win *gtk.ApplicationWindow, openFileName *string, userTX *[]lib.TX) {
-	if *openFileName != "" {
-		var err error
-		*userTX, err = oldutil.LoadConfig(*openFileName)
+func ProcessInitialConfigLoad(ws *state.WinState) {
+	if ws.OpenFileName != "" {
+		conf, err := oldutil.LoadConfig(ws.OpenFileName)
+		SetConf(ws, conf)
 		if err != nil {
-			ConfigLoadErrorPromptFlow(win, *openFileName, userTX)
+			ConfigLoadErrorPromptFlow(ws)
 		}
 	}
 
-	if len(*userTX) == 0 {
-		newTX := lib.GetNewTX(time.Now())
-		// newTX.Order = 1
-		*userTX = []lib.TX{newTX}
-		EmptyConfigLoadSuccessDialog(win, *openFileName)
+	if len(*ws.TX) == 0 {
+		newTX := planner.GetNewTX(time.Now(), nil)
+		*ws.TX = []planner.TX{newTX}
+		EmptyConfigLoadSuccessDialog(ws.Win, ws.OpenFileName)
+	}
+}
+
+// GetConf gathers everything that gets saved to a config file from the
+// provided window's state.
+func GetConf(ws *state.WinState) planner.Conf {
+	return planner.Conf{
+		Transactions:   *ws.TX,
+		ConfigSort:     ws.ConfigSort,
+		HolidaysFile:   ws.HolidaysFile,
+		Variables:      ws.Variables,
+		Sweeps:         ws.Sweeps,
+		Interest:       ws.Interest,
+		Goals:          ws.Goals,
+		MinimumBalance: ws.MinimumBalance,
+	}
+}
+
+// SetConf applies the contents of a loaded config file to the provided
+// window's state. The list stores are not synced.
+func SetConf(ws *state.WinState, conf planner.Conf) {
+	*ws.TX = conf.Transactions
+	ws.ConfigSort = conf.ConfigSort
+	ws.Variables = conf.Variables
+	ws.Sweeps = conf.Sweeps
+	ws.Interest = conf.Interest
+	ws.Goals = conf.Goals
+	ws.MinimumBalance = conf.MinimumBalance
+	ws.HolidaysFile = ""
+	ws.Holidays = nil
+
+	setMinimumBalanceText(ws)
+
+	err := SetHolidaysFile(ws, conf.HolidaysFile)
+	if err != nil {
+		(*ws.ShowMessageDialog)(fmt.Sprintf(c.MsgHolidaysLoadFailed, err.Error()), gtk.MESSAGE_WARNING)
 	}
 }
 
 // ConfigLoadErrorPromptFlow occurs when the application tries to load the user
 // transactions from a config file, but the file is either invalid, empty,
 // or any other error present when loading.
-func ConfigLoadErrorPromptFlow(win *gtk.ApplicationWindow, openFileName string, userTX *[]lib.TX) {
+func ConfigLoadErrorPromptFlow(ws *state.WinState) {
 	m := fmt.Sprintf(
 		"Config does not exist (or is not accessible) at %v. Would you like to create a new one there now?",
-		openFileName,
+		ws.OpenFileName,
 	)
 
-	d := gtk.MessageDialogNew(win,
+	d := gtk.MessageDialogNew(ws.Win,
 		gtk.DIALOG_MODAL,
 		gtk.MESSAGE_QUESTION,
 		gtk.BUTTONS_YES_NO,
@@ -79,14 +116,14 @@ func ConfigLoadErrorPromptFlow(win *gtk.ApplicationWindow, openFileName string,
 
 	resp := d.Run()
 	if resp == gtk.RESPONSE_YES {
-		err := oldutil.SaveConfig(openFileName, *userTX)
+		err := oldutil.SaveConfig(ws.OpenFileName, GetConf(ws))
 		if err != nil {
 			m := fmt.Sprintf(
 				"Failed to save config upon window load - will proceed with a blank config. Here's the error: %v",
 				err.Error(),
 			)
 			di := gtk.MessageDialogNew(
-				win,
+				ws.Win,
 				gtk.DIALOG_MODAL,
 				gtk.MESSAGE_ERROR,
 				gtk.BUTTONS_OK,
@@ -168,6 +205,22 @@ func GetMainWindowRootElements(application *gtk.Application) (
 	menu.Append(c.MenuItemOpenNewWindow, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionLoadConfigNewWindow))
 	menu.Append(c.MenuItemSaveResults, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionSaveResults))
 	menu.Append(c.MenuItemCopyResults, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionCopyResults))
+	menu.Append(c.MenuItemCopyTX, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionCopyTX))
+	menu.Append(c.MenuItemCopyTXAsTSV, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionCopyTXAsTSV))
+	menu.Append(c.MenuItemPasteTX, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionPasteTX))
+	menu.Append(c.MenuItemEditRRule, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditRRule))
+	menu.Append(c.MenuItemEditSeasonal, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditSeasonal))
+	menu.Append(c.MenuItemEditVariables, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditVariables))
+	menu.Append(c.MenuItemEditSweeps, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditSweeps))
+	menu.Append(c.MenuItemEditInterest, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditInterest))
+	menu.Append(c.MenuItemNewLoan, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionNewLoan))
+	menu.Append(c.MenuItemEditLoan, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditLoan))
+	menu.Append(c.MenuItemDebtPlanner, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionDebtPlanner))
+	menu.Append(c.MenuItemEditGoals, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionEditGoals))
+	menu.Append(c.MenuItemSinkingFunds, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionSinkingFunds))
+	menu.Append(c.MenuItemPayPeriods, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionPayPeriods))
+	menu.Append(c.MenuItemLoadHolidays, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionLoadHolidays))
+	menu.Append(c.MenuItemDefaultHolidays, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionDefaultHolidays))
 	menu.Append(c.MenuItemShowStats, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionGetStats))
 	menu.Append(c.MenuItemAbout, fmt.Sprintf("%v.%v", c.ActionGroupFin, c.ActionAbout))
 	menu.Append(c.MenuItemNewWindow, fmt.Sprintf("%v.%v", c.ActionGroupApp, c.ActionNew))
@@ -179,17 +232,17 @@ func GetMainWindowRootElements(application *gtk.Application) (
 
 // TODO: refactor dialog code
 // TODO: clean up logging
-func SaveOpenConf(win *gtk.ApplicationWindow, header *gtk.HeaderBar, openFileName *string, userTX *[]lib.TX) {
+func SaveOpenConf(ws *state.WinState) {
 	// write the config to the target file path
-	err := oldutil.SaveConfig(*openFileName, *userTX)
+	err := oldutil.SaveConfig(ws.OpenFileName, GetConf(ws))
 	if err != nil {
 		m := fmt.Sprintf(
 			"Failed to save config to file \"%v\": %v",
-			openFileName,
+			ws.OpenFileName,
 			err.Error(),
 		)
 		d := gtk.MessageDialogNew(
-			win,
+			ws.Win,
 			gtk.DIALOG_MODAL,
 			gtk.MESSAGE_ERROR,
 			gtk.BUTTONS_OK,
@@ -201,15 +254,15 @@ func SaveOpenConf(win *gtk.ApplicationWindow, header *gtk.HeaderBar, openFileNam
 		d.Destroy()
 		return
 	}
-	header.SetSubtitle(*openFileName)
+	ws.Header.SetSubtitle(ws.OpenFileName)
 }
 
 // TODO: refactor dialog code
 // TODO: clean up logging
-func SaveConfAs(win *gtk.ApplicationWindow, header *gtk.HeaderBar, openFileName *string, userTX *[]lib.TX) {
+func SaveConfAs(ws *state.WinState) {
 	p, err := gtk.FileChooserDialogNewWith2Buttons(
 		"Save config",
-		win,
+		ws.Win,
 		gtk.FILE_CHOOSER_ACTION_SAVE,
 		"_Save",
 		gtk.RESPONSE_OK,
@@ -226,17 +279,17 @@ func SaveConfAs(win *gtk.ApplicationWindow, header *gtk.HeaderBar, openFileName
 		if resp == int(gtk.RESPONSE_OK) {
 			// folder, _ := dialog.FileChooser.GetCurrentFolder()
 			// GetFilename includes the full path and file name
-			*openFileName = dialog.FileChooser.GetFilename()
+			ws.OpenFileName = dialog.FileChooser.GetFilename()
 			// write the config to the target file path
-			err := oldutil.SaveConfig(*openFileName, *userTX)
+			err := oldutil.SaveConfig(ws.OpenFileName, GetConf(ws))
 			if err != nil {
 				m := fmt.Sprintf(
 					"Failed to save config to file \"%v\": %v",
-					*openFileName,
+					ws.OpenFileName,
 					err.Error(),
 				)
 				d := gtk.MessageDialogNew(
-					win,
+					ws.Win,
 					gtk.DIALOG_MODAL,
 					gtk.MESSAGE_ERROR,
 					gtk.BUTTONS_OK,
@@ -248,7 +301,7 @@ func SaveConfAs(win *gtk.ApplicationWindow, header *gtk.HeaderBar, openFileName
 				d.Destroy()
 				return
 			}
-			header.SetSubtitle(*openFileName)
+			ws.Header.SetSubtitle(ws.OpenFileName)
 		}
 		p.Close()
 	})
@@ -425,22 +478,27 @@ func SetupNotebookPages(ws *state.WinState) (*gtk.Grid, *gtk.Grid) {
 
 	now := time.Now()
 
-	*ws.Results, err = lib.GetResults(
+	*ws.Results, err = planner.GetResults(
 		*ws.TX,
 		lib.GetDateFromStrSafe(ws.StartDate, now),
 		lib.GetDateFromStrSafe(ws.EndDate, now),
 		ws.StartingBalance,
-		func(_ string) {},
+		ws.Holidays,
+		ws.Sweeps,
+		ws.Interest,
 	)
 	if err != nil {
 		log.Fatal("failed to generate results from date strings", err.Error())
 	}
 
-	resultsGrid, label, err := GenerateResultsTab(
-		ws.TX,
-		*ws.Results,
-		ws.ResultsListStore,
+	ws.SinkingFunds = planner.GetSinkingFundBalances(
+		*ws.TX,
+		lib.GetDateFromStrSafe(ws.StartDate, now),
+		lib.GetDateFromStrSafe(ws.EndDate, now),
+		ws.Holidays,
 	)
+
+	resultsGrid, label, err := GenerateResultsTab(ws)
 	if err != nil {
 		log.Fatalf("failed to generate results tab: %v", err.Error())
 	}
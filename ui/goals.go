@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// syncGoalsListStore fills the list store with the status of each goal, with
+// a string for each of constants.GoalColumns.
+func syncGoalsListStore(ls *gtk.ListStore, statuses []planner.GoalStatus) {
+	ls.Clear()
+
+	columns := []int{}
+	for i := range constants.GoalColumns {
+		columns = append(columns, i)
+	}
+
+	for _, s := range statuses {
+		projected := ""
+		if s.InRange {
+			projected = lib.FormatAsCurrency(s.Projected)
+		}
+
+		err := ls.Set(ls.Append(), columns, []interface{}{
+			glib.MarkupEscapeText(s.Goal.Name),
+			lib.FormatAsCurrency(s.Goal.Target),
+			s.Goal.Date,
+			projected,
+			glib.MarkupEscapeText(s.GetStatusText()),
+		})
+		if err != nil {
+			log.Printf("failed to add goal row: %v", err.Error())
+		}
+	}
+}
+
+// ShowGoalsEditor shows a dialog for editing the plan's savings goals, with
+// one per line (see planner.ParseGoals). As they're typed, each goal is
+// checked against the current results, showing whether it's on track, the
+// projected surplus or shortfall on its date, and how much more would have
+// to be saved each month to reach it.
+func ShowGoalsEditor(ws *state.WinState) {
+	d, err := gtk.DialogNewWithButtons(
+		constants.GoalsEditorTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.GoalsEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.GoalsEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create goals editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.GoalsEditorWidth, constants.GoalsEditorHeight)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get goals editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	view, err := gtk.TextViewNew()
+	if err != nil {
+		log.Printf("failed to create goals editor input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	view.SetMonospace(true)
+
+	buf, err := view.GetBuffer()
+	if err != nil {
+		log.Printf("failed to get goals editor buffer: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	buf.SetText(planner.GetGoalsText(ws.Goals))
+
+	inputSw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create goals editor scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	inputSw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	inputSw.SetShadowType(gtk.SHADOW_IN)
+	inputSw.SetSizeRequest(-1, constants.GoalsEditorInputHeight)
+	inputSw.Add(view)
+
+	status := newDetailLabel("")
+
+	types := []glib.Type{}
+	for range constants.GoalColumns {
+		types = append(types, glib.TYPE_STRING)
+	}
+
+	ls, err := gtk.ListStoreNew(types...)
+	if err != nil {
+		log.Printf("failed to create goals list store: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	tv, err := gtk.TreeViewNewWithModel(ls)
+	if err != nil {
+		log.Printf("failed to create goals tree view: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for i, column := range constants.GoalColumns {
+		tvc, err := createColumn(column, i)
+		if err != nil {
+			log.Printf("failed to create goals column %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tv.AppendColumn(tvc)
+	}
+
+	statusSw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create goals status scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	statusSw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	statusSw.SetShadowType(gtk.SHADOW_IN)
+	statusSw.SetVExpand(true)
+	statusSw.Add(tv)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get goals editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getText := func() string {
+		start, end := buf.GetBounds()
+		text, err := buf.GetText(start, end, true)
+		if err != nil {
+			log.Printf("failed to get goals editor text: %v", err.Error())
+		}
+
+		return text
+	}
+
+	check := func() {
+		goals, err := planner.ParseGoals(getText())
+		applyBtn.ToWidget().SetSensitive(err == nil)
+
+		if err != nil {
+			status.SetText(err.Error())
+			ls.Clear()
+			return
+		}
+
+		statuses := planner.GetGoalStatuses(goals, *ws.Results)
+		onTrack := 0
+		for _, s := range statuses {
+			if s.IsOnTrack() {
+				onTrack++
+			}
+		}
+
+		status.SetText(fmt.Sprintf(constants.GoalsEditorSummary, onTrack, len(statuses)))
+		syncGoalsListStore(ls, statuses)
+	}
+
+	buf.Connect(constants.GtkSignalChanged, check)
+	check()
+
+	content.PackStart(newDetailLabel(constants.GoalsEditorHelp), false, false, 0)
+	content.PackStart(inputSw, false, false, 0)
+	content.PackStart(status, false, false, 0)
+	content.PackStart(statusSw, true, true, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	text := getText()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK {
+		return
+	}
+
+	goals, err := planner.ParseGoals(text)
+	if err != nil {
+		log.Printf("failed to parse goals: %v", err.Error())
+		return
+	}
+
+	if planner.GetGoalsText(goals) == planner.GetGoalsText(ws.Goals) {
+		return
+	}
+
+	ws.Goals = goals
+	UpdateResults(ws, false)
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// SetHolidaysFile loads the holiday calendar that transactions are moved off
+// of from the provided file, or goes back to the US federal holidays if file
+// is empty. If the file can't be loaded, the window's state is left as it is.
+// Nothing is synced.
+func SetHolidaysFile(ws *state.WinState, file string) error {
+	if file == "" {
+		ws.HolidaysFile = ""
+		ws.Holidays = nil
+
+		return nil
+	}
+
+	h, err := planner.LoadHolidays(file)
+	if err != nil {
+		return err
+	}
+
+	ws.HolidaysFile = file
+	ws.Holidays = h
+
+	return nil
+}
+
+// refreshHolidays updates everything that depends on the holiday calendar
+// after it changes.
+func refreshHolidays(ws *state.WinState) {
+	err := SyncConfigListStore(ws)
+	if err != nil {
+		log.Printf("failed to sync config list store after holiday calendar change: %v", err.Error())
+	}
+
+	UpdateResults(ws, false)
+	RefreshDetailPanel(ws)
+}
+
+// LoadHolidays launches the GTK file chooser dialog and loads a holiday
+// calendar file (see planner.ParseHolidays) for the current plan.
+func LoadHolidays(ws *state.WinState) {
+	p, err := gtk.FileChooserDialogNewWith2Buttons(
+		constants.HolidaysFileChooserTitle,
+		ws.Win,
+		gtk.FILE_CHOOSER_ACTION_OPEN,
+		constants.HolidaysFileChooserLoad,
+		gtk.RESPONSE_OK,
+		constants.HolidaysFileChooserCancel,
+		gtk.RESPONSE_CANCEL,
+	)
+	if err != nil {
+		log.Printf("failed to create holiday calendar file picker: %v", err.Error())
+		return
+	}
+
+	if ws.HolidaysFile != "" {
+		p.SetFilename(ws.HolidaysFile)
+	}
+
+	resp := p.Run()
+	file := p.GetFilename()
+	p.Destroy()
+
+	if resp != gtk.RESPONSE_OK {
+		return
+	}
+
+	err = SetHolidaysFile(ws, file)
+	if err != nil {
+		(*ws.ShowMessageDialog)(err.Error(), gtk.MESSAGE_ERROR)
+		return
+	}
+
+	refreshHolidays(ws)
+	(*ws.ShowMessageDialog)(fmt.Sprintf(constants.MsgHolidaysLoaded, len(ws.Holidays), file), gtk.MESSAGE_INFO)
+}
+
+// UseDefaultHolidays goes back to moving transactions off of the built-in US
+// federal holidays for the current plan.
+func UseDefaultHolidays(ws *state.WinState) {
+	if ws.HolidaysFile != "" {
+		_ = SetHolidaysFile(ws, "")
+		refreshHolidays(ws)
+	}
+
+	(*ws.ShowMessageDialog)(constants.MsgHolidaysDefault, gtk.MESSAGE_INFO)
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// ShowInterestEditor shows a dialog for the plan's interest settings: the
+// rates that are earned and charged on positive and negative balances, how
+// often interest compounds, and the day of the month that it's posted on.
+// Interest can also be turned off entirely.
+func ShowInterestEditor(ws *state.WinState) {
+	in := planner.Interest{Compounding: constants.InterestDaily, PostingDay: 1}
+	if ws.Interest != nil {
+		in = *ws.Interest
+	}
+
+	d, err := gtk.DialogNewWithButtons(
+		constants.InterestEditorTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.InterestEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.InterestEditorRemove, gtk.RESPONSE_REJECT},
+		[]interface{}{constants.InterestEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create interest editor dialog: %v", err.Error())
+		return
+	}
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get interest editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create interest editor grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetRowSpacing(constants.UISpacer / 2)
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	savingsRate, err := gtk.SpinButtonNewWithRange(0, 100, 0.05)
+	if err != nil {
+		log.Printf("failed to create interest editor savings rate input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	debtRate, err := gtk.SpinButtonNewWithRange(0, 100, 0.05)
+	if err != nil {
+		log.Printf("failed to create interest editor debt rate input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	compounding, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Printf("failed to create interest editor compounding picker: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	postingDay, err := gtk.SpinButtonNewWithRange(1, 31, 1)
+	if err != nil {
+		log.Printf("failed to create interest editor posting day input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	savingsRate.SetDigits(3)
+	savingsRate.SetValue(in.SavingsRate)
+	debtRate.SetDigits(3)
+	debtRate.SetValue(in.DebtRate)
+	compounding.Append(constants.InterestDaily, constants.InterestEditorDaily)
+	compounding.Append(constants.InterestMonthly, constants.InterestEditorMonthly)
+	compounding.SetActiveID(in.Compounding)
+	if compounding.GetActiveID() == "" {
+		compounding.SetActiveID(constants.InterestDaily)
+	}
+	postingDay.SetValue(float64(in.PostingDay))
+
+	grid.Attach(newDetailLabel(constants.InterestEditorSavingsRate), 0, 0, 1, 1)
+	grid.Attach(savingsRate, 1, 0, 1, 1)
+	grid.Attach(newDetailLabel(constants.InterestEditorDebtRate), 0, 1, 1, 1)
+	grid.Attach(debtRate, 1, 1, 1, 1)
+	grid.Attach(newDetailLabel(constants.InterestEditorCompounding), 0, 2, 1, 1)
+	grid.Attach(compounding, 1, 2, 1, 1)
+	grid.Attach(newDetailLabel(constants.InterestEditorPostingDay), 0, 3, 1, 1)
+	grid.Attach(postingDay, 1, 3, 1, 1)
+
+	removeBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_REJECT)
+	if err != nil {
+		log.Printf("failed to get interest editor remove button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	removeBtn.ToWidget().SetSensitive(ws.Interest != nil)
+
+	content.PackStart(newDetailLabel(constants.InterestEditorHelp), false, false, 0)
+	content.PackStart(grid, true, true, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+
+	in.SavingsRate = savingsRate.GetValue()
+	in.DebtRate = debtRate.GetValue()
+	in.Compounding = compounding.GetActiveID()
+	in.PostingDay = postingDay.GetValueAsInt()
+
+	d.Destroy()
+
+	switch resp {
+	case gtk.RESPONSE_OK:
+		if err := planner.ValidateInterest(in); err != nil {
+			(*ws.ShowMessageDialog)(err.Error(), gtk.MESSAGE_ERROR)
+			return
+		}
+
+		ws.Interest = &in
+	case gtk.RESPONSE_REJECT:
+		ws.Interest = nil
+	default:
+		return
+	}
+
+	UpdateResults(ws, false)
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// parseLoanAmount parses an amount in the loan editor, such as "$25,000",
+// which is always positive. An empty value is 0.
+func parseLoanAmount(s string) int {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0
+	}
+
+	return int(math.Abs(float64(lib.ParseDollarAmount(s, true))))
+}
+
+// newLoanScheduleListStore creates the list store for a loan's amortization
+// schedule, with a string for each of constants.LoanScheduleColumns.
+func newLoanScheduleListStore() (*gtk.ListStore, error) {
+	types := []glib.Type{}
+	for range constants.LoanScheduleColumns {
+		types = append(types, glib.TYPE_STRING)
+	}
+
+	ls, err := gtk.ListStoreNew(types...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create loan schedule list store: %v", err.Error())
+	}
+
+	return ls, nil
+}
+
+// syncLoanScheduleListStore fills the list store with the loan's schedule.
+func syncLoanScheduleListStore(ls *gtk.ListStore, schedule []planner.LoanPayment) {
+	ls.Clear()
+
+	columns := []int{}
+	for i := range constants.LoanScheduleColumns {
+		columns = append(columns, i)
+	}
+
+	for _, p := range schedule {
+		err := ls.Set(ls.Append(), columns, []interface{}{
+			strconv.Itoa(p.Number),
+			lib.GetNowDateString(p.Date),
+			lib.FormatAsCurrency(p.Payment),
+			lib.FormatAsCurrency(p.Interest),
+			lib.FormatAsCurrency(p.Principal),
+			lib.FormatAsCurrency(p.Balance),
+		})
+		if err != nil {
+			log.Printf("failed to add loan schedule row: %v", err.Error())
+		}
+	}
+}
+
+// ShowLoanEditor shows a dialog for the loan of the TX with the provided ID,
+// or for a new loan if id is empty. The loan's payment, payoff date and
+// amortization schedule are updated as it's edited, such as when the extra
+// payment changes. Applying it turns the TX into the loan's monthly payment
+// (see planner.ApplyLoan), adding a new TX for a new loan.
+func ShowLoanEditor(ws *state.WinState, id string) {
+	now := time.Now()
+	name := constants.LoanEditorDefaultName
+	loan := planner.Loan{
+		Rate:  constants.LoanEditorDefaultRate,
+		Term:  constants.LoanEditorDefaultTerm,
+		Start: lib.GetNowDateString(time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	title := constants.LoanEditorNewTitle
+	apply := constants.LoanEditorAdd
+
+	if id != "" {
+		i, err := planner.GetTXByID(ws.TX, id)
+		if err != nil || (*ws.TX)[i].Loan == nil {
+			log.Printf("failed to find the loan of tx %v", id)
+			return
+		}
+
+		name = (*ws.TX)[i].Name
+		loan = *(*ws.TX)[i].Loan
+		title = fmt.Sprintf("%v - %v", constants.LoanEditorTitle, name)
+		apply = constants.LoanEditorApply
+	}
+
+	d, err := gtk.DialogNewWithButtons(
+		title,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.LoanEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{apply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create loan editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.LoanEditorWidth, constants.LoanEditorHeight)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get loan editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create loan editor grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetRowSpacing(constants.UISpacer / 2)
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	entries := []*gtk.Entry{}
+	newEntry := func(text string) *gtk.Entry {
+		e, err := gtk.EntryNew()
+		if err != nil {
+			log.Fatalf("failed to create loan editor entry: %v", err.Error())
+		}
+
+		e.SetText(text)
+		entries = append(entries, e)
+
+		return e
+	}
+
+	nameEntry := newEntry(name)
+	principal := newEntry("")
+	if loan.Principal > 0 {
+		principal.SetText(lib.FormatAsCurrency(loan.Principal))
+	}
+
+	principal.SetPlaceholderText(constants.LoanEditorPrincipalPlaceholder)
+	start := newEntry(loan.Start)
+	extra := newEntry(lib.FormatAsCurrency(loan.Extra))
+
+	rate, err := gtk.SpinButtonNewWithRange(0, 100, 0.125)
+	if err != nil {
+		log.Printf("failed to create loan editor rate input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	rate.SetDigits(3)
+	rate.SetValue(loan.Rate)
+
+	term, err := gtk.SpinButtonNewWithRange(1, constants.LoanEditorMaxTerm, 1)
+	if err != nil {
+		log.Printf("failed to create loan editor term input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	term.SetValue(float64(loan.Term))
+
+	rows := []struct {
+		label  string
+		widget gtk.IWidget
+	}{
+		{constants.LoanEditorName, nameEntry},
+		{constants.LoanEditorPrincipal, principal},
+		{constants.LoanEditorRate, rate},
+		{constants.LoanEditorTerm, term},
+		{constants.LoanEditorStart, start},
+		{constants.LoanEditorExtra, extra},
+	}
+
+	for i, row := range rows {
+		grid.Attach(newDetailLabel(row.label), 0, i, 1, 1)
+		grid.Attach(row.widget, 1, i, 1, 1)
+	}
+
+	summary := newDetailLabel("")
+
+	ls, err := newLoanScheduleListStore()
+	if err != nil {
+		log.Print(err.Error())
+		d.Destroy()
+		return
+	}
+
+	tv, err := gtk.TreeViewNewWithModel(ls)
+	if err != nil {
+		log.Printf("failed to create loan schedule tree view: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for i, column := range constants.LoanScheduleColumns {
+		tvc, err := createColumn(column, i)
+		if err != nil {
+			log.Printf("failed to create loan schedule column %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tv.AppendColumn(tvc)
+	}
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create loan schedule scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetVExpand(true)
+	sw.Add(tv)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get loan editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getLoan := func() planner.Loan {
+		p, _ := principal.GetText()
+		s, _ := start.GetText()
+		e, _ := extra.GetText()
+
+		return planner.Loan{
+			Principal: parseLoanAmount(p),
+			Rate:      rate.GetValue(),
+			Term:      term.GetValueAsInt(),
+			Start:     strings.TrimSpace(s),
+			Extra:     parseLoanAmount(e),
+		}
+	}
+
+	check := func() {
+		l := getLoan()
+		err := planner.ValidateLoan(l)
+		applyBtn.ToWidget().SetSensitive(err == nil)
+
+		if err != nil {
+			summary.SetText(err.Error())
+			ls.Clear()
+			return
+		}
+
+		summary.SetText(l.GetLoanSummary())
+		syncLoanScheduleListStore(ls, l.GetSchedule())
+	}
+
+	for _, e := range entries {
+		e.Connect(constants.GtkSignalChanged, check)
+	}
+
+	rate.Connect(constants.GtkSignalValueChanged, check)
+	term.Connect(constants.GtkSignalValueChanged, check)
+	check()
+
+	content.PackStart(newDetailLabel(constants.LoanEditorHelp), false, false, 0)
+	content.PackStart(grid, false, false, 0)
+	content.PackStart(summary, false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	loan = getLoan()
+	name, _ = nameEntry.GetText()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK || planner.ValidateLoan(loan) != nil {
+		return
+	}
+
+	if id == "" {
+		tx := planner.GetNewTX(now, *ws.TX)
+		tx.Name = name
+		planner.ApplyLoan(&tx, loan)
+		*ws.TX = append(*ws.TX, tx)
+
+		// new conf items are added at the bottom, as in AddConfItem
+		SetConfigScrollPosition(ws, 65535, -1)
+		ClearAllSelections(ws)
+		UpdateResults(ws, false)
+		SyncConfigListStore(ws)
+		RestoreConfigScrollPosition(ws)
+
+		return
+	}
+
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("failed to find tx %v to update its loan: %v", id, err.Error())
+		return
+	}
+
+	tx := &(*ws.TX)[i]
+	tx.Name = name
+	tx.UpdatedAt = now
+	planner.ApplyLoan(tx, loan)
+
+	err = SyncConfigListStore(ws)
+	if err != nil {
+		log.Printf("failed to sync config list store after loan change: %v", err.Error())
+	}
+
+	UpdateResults(ws, false)
+	RefreshDetailPanel(ws)
+}
+
+// EditSelectedLoan shows the loan editor (with its amortization schedule) for
+// the selected TX, if exactly one TX is selected and it was generated from a
+// loan.
+func EditSelectedLoan(ws *state.WinState) {
+	selected := getSelectedTX(ws)
+	if len(selected) != 1 || selected[0].Loan == nil {
+		(*ws.ShowMessageDialog)(constants.MsgSelectOneToEditLoan, gtk.MESSAGE_INFO)
+		return
+	}
+
+	ShowLoanEditor(ws, selected[0].ID)
+}
@@ -0,0 +1,247 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// syncPayPeriodsListStore fills the list store with the pay periods, with a
+// string for each of constants.PayPeriodColumns, followed by a bool that
+// highlights the periods that run short.
+func syncPayPeriodsListStore(ls *gtk.ListStore, periods []planner.PayPeriod) {
+	ls.Clear()
+
+	columns := []int{}
+	for i := range constants.PayPeriodColumns {
+		columns = append(columns, i)
+	}
+
+	columns = append(columns, len(constants.PayPeriodColumns))
+
+	for _, p := range periods {
+		payday := constants.PayPeriodNoPayday
+		if !p.Payday.IsZero() {
+			payday = p.Payday.Format(constants.DetailDateFormat)
+		}
+
+		bills := []string{}
+		for _, b := range p.Bills {
+			bills = append(bills, fmt.Sprintf(constants.PayPeriodBillFormat, b.Name, lib.FormatAsCurrency(b.Amount), lib.GetNowDateString(b.Date)))
+		}
+
+		suggestion := ""
+		if p.Suggestion != nil {
+			suggestion = fmt.Sprintf(
+				constants.PayPeriodSuggestionFormat,
+				p.Suggestion.Name,
+				lib.FormatAsCurrency(p.Suggestion.Amount),
+				p.Suggestion.Date.Format(constants.DetailDateFormat),
+			)
+		}
+
+		err := ls.Set(ls.Append(), columns, []interface{}{
+			payday,
+			p.End.Format(constants.DetailDateFormat),
+			lib.FormatAsCurrency(p.Income),
+			glib.MarkupEscapeText(strings.Join(bills, constants.PayPeriodBillSeparator)),
+			lib.FormatAsCurrency(p.Committed),
+			lib.FormatAsCurrency(p.GetLeft()),
+			lib.FormatAsCurrency(p.Balance),
+			glib.MarkupEscapeText(suggestion),
+			p.IsShort(),
+		})
+		if err != nil {
+			log.Printf("failed to add pay period row: %v", err.Error())
+		}
+	}
+}
+
+// getDefaultPayPeriodIncome returns the ID of the paycheck that was last
+// chosen in the pay periods view if it's still one of the incomes, or the
+// income with the largest yearly amount otherwise.
+func getDefaultPayPeriodIncome(ws *state.WinState, incomes []*planner.TX, now time.Time) string {
+	id, largest := "", 0
+	for _, tx := range incomes {
+		if tx.ID == ws.PayPeriodIncome {
+			return tx.ID
+		}
+
+		if yearly := planner.GetYearlyAmount(tx, now); id == "" || yearly > largest {
+			id, largest = tx.ID, yearly
+		}
+	}
+
+	return id
+}
+
+// ShowPayPeriods shows a dialog that splits the results into pay periods
+// between the paydays of a chosen income transaction (see
+// planner.GetPayPeriods), listing each period's bills, the total that's
+// committed to them and what's left. Periods that run short are highlighted,
+// along with a bill that could be paid from the previous paycheck instead.
+func ShowPayPeriods(ws *state.WinState) {
+	now := time.Now()
+
+	d, err := gtk.DialogNewWithButtons(
+		constants.PayPeriodsTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.PayPeriodsClose, gtk.RESPONSE_CLOSE},
+	)
+	if err != nil {
+		log.Printf("failed to create pay periods dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.PayPeriodsWidth, constants.PayPeriodsHeight)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get pay periods content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	incomes := []*planner.TX{}
+	for i := range *ws.TX {
+		tx := &(*ws.TX)[i]
+		if tx.Active && planner.GetYearlyAmount(tx, now) > 0 {
+			incomes = append(incomes, tx)
+		}
+	}
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create pay periods grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	income, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		log.Printf("failed to create pay periods income picker: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for _, tx := range incomes {
+		income.Append(tx.ID, fmt.Sprintf("%v (%v)", tx.Name, lib.FormatAsCurrency(tx.Amount)))
+	}
+
+	grid.Attach(newDetailLabel(constants.PayPeriodsIncome), 0, 0, 1, 1)
+	grid.Attach(income, 1, 0, 1, 1)
+
+	summary := newDetailLabel("")
+
+	types := []glib.Type{}
+	for range constants.PayPeriodColumns {
+		types = append(types, glib.TYPE_STRING)
+	}
+
+	// whether the period runs short
+	types = append(types, glib.TYPE_BOOLEAN)
+
+	ls, err := gtk.ListStoreNew(types...)
+	if err != nil {
+		log.Printf("failed to create pay periods list store: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	tv, err := gtk.TreeViewNewWithModel(ls)
+	if err != nil {
+		log.Printf("failed to create pay periods tree view: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for i, column := range constants.PayPeriodColumns {
+		r, err := gtk.CellRendererTextNew()
+		if err != nil {
+			log.Printf("failed to create pay periods renderer %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tvc, err := gtk.TreeViewColumnNewWithAttribute(column, r, "markup", i)
+		if err != nil {
+			log.Printf("failed to create pay periods column %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tvc.SetResizable(true)
+		r.SetProperty("cell-background", constants.ProblemCellBackground)
+		tvc.AddAttribute(r, "cell-background-set", len(constants.PayPeriodColumns))
+		tv.AppendColumn(tvc)
+	}
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create pay periods scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetVExpand(true)
+	sw.Add(tv)
+
+	check := func() {
+		id := income.GetActiveID()
+		if id == "" {
+			summary.SetText(constants.PayPeriodsNoIncome)
+			ls.Clear()
+			return
+		}
+
+		ws.PayPeriodIncome = id
+
+		periods := planner.GetPayPeriods(*ws.TX, id, *ws.Results, ws.Holidays)
+		short := 0
+		for i := range periods {
+			if periods[i].IsShort() {
+				short++
+			}
+		}
+
+		summary.SetText(fmt.Sprintf(constants.PayPeriodsSummary, short, len(periods)))
+		syncPayPeriodsListStore(ls, periods)
+	}
+
+	income.Connect(constants.GtkSignalChanged, check)
+
+	content.PackStart(newDetailLabel(constants.PayPeriodsHelp), false, false, 0)
+	content.PackStart(grid, false, false, 0)
+	content.PackStart(summary, false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.ShowAll()
+
+	if id := getDefaultPayPeriodIncome(ws, incomes, now); id != "" {
+		income.SetActiveID(id)
+	}
+
+	check()
+
+	d.Run()
+	d.Destroy()
+}
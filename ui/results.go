@@ -5,6 +5,7 @@ import (
 	"log"
 
 	c "github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
 	"github.com/charles-m-knox/gtk-finance-planner/state"
 
 	lib "github.com/charles-m-knox/finance-planner-lib"
@@ -26,6 +27,7 @@ func GetNewResultsListStore() (ls *gtk.ListStore, err error) {
 		glib.TYPE_STRING,
 		glib.TYPE_STRING,
 		glib.TYPE_STRING,
+		glib.TYPE_STRING,
 	)
 	if err != nil {
 		return ls, fmt.Errorf("unable to create results list store: %v", err.Error())
@@ -62,14 +64,29 @@ func setupTreeView(ls *gtk.ListStore) (tv *gtk.TreeView, err error) {
 	return treeView, nil
 }
 
-// Append a row to the list store for the tree view
-func addRow(listStore *gtk.ListStore, result *lib.Result) error {
+// getBalanceMarkup formats a result's balance, followed by a marker for each
+// of the savings goals that are due on its day, such as "$11500.00 ⚑
+// Emergency fund ($12000.00)".
+func getBalanceMarkup(result *lib.Result, goals []planner.Goal) string {
+	balance := lib.FormatAsCurrency(result.Balance)
+
+	for _, g := range goals {
+		balance = fmt.Sprintf(c.GoalMarkerFormat, balance, glib.MarkupEscapeText(g.Name), lib.FormatAsCurrency(g.Target))
+	}
+
+	return balance
+}
+
+// Append a row to the list store for the tree view, marking the savings goals
+// that are due on its day in the balance column, along with the day's sinking
+// fund balances
+func addRow(listStore *gtk.ListStore, result *lib.Result, goals []planner.Goal, funds string) error {
 	// get an iterator for a new row at the end of the list store
 	iter := listStore.Append()
 
 	rowData := []interface{}{
 		lib.GetNowDateString(result.Date),
-		lib.FormatAsCurrency(result.Balance),              // lib.CurrencyMarkup(result.Balance),
+		getBalanceMarkup(result, goals),                   // lib.CurrencyMarkup(result.Balance),
 		lib.FormatAsCurrency(result.CumulativeIncome),     // lib.CurrencyMarkup(result.CumulativeIncome),
 		lib.FormatAsCurrency(result.CumulativeExpenses),   // lib.CurrencyMarkup(result.CumulativeExpenses),
 		lib.FormatAsCurrency(result.DayExpenses),          // lib.CurrencyMarkup(result.DayExpenses),
@@ -77,6 +94,7 @@ func addRow(listStore *gtk.ListStore, result *lib.Result) error {
 		lib.FormatAsCurrency(result.DayNet),               // lib.CurrencyMarkup(result.DayNet),
 		lib.FormatAsCurrency(result.DiffFromStart),        // lib.CurrencyMarkup(result.DiffFromStart),
 		lib.GetCSVString(result.DayTransactionNamesSlice), // lib.MarkupColorSequence(result.DayTransactionNamesSlice),
+		glib.MarkupEscapeText(funds),
 	}
 
 	// Set the contents of the list store row that the iterator represents
@@ -89,16 +107,25 @@ func addRow(listStore *gtk.ListStore, result *lib.Result) error {
 }
 
 // SyncResultsListStore clears and populates the target list store with all
-// of the provided results
-func SyncResultsListStore(results *[]lib.Result, ls *gtk.ListStore) error {
+// of the provided results, marking the provided savings goals. funds are the
+// sinking fund balances of each result (see planner.GetSinkingFundBalances),
+// and may be empty.
+func SyncResultsListStore(results *[]lib.Result, ls *gtk.ListStore, goals []planner.Goal, funds []string) error {
 	if ls == nil {
 		return fmt.Errorf("results list store cannot sync; is nil")
 	}
 	ls.Clear()
 
+	markers := planner.GetGoalMarkers(goals)
+
 	// add rows to the tree's list store
-	for _, result := range *results {
-		err := addRow(ls, &result)
+	for i, result := range *results {
+		f := ""
+		if i < len(funds) {
+			f = funds[i]
+		}
+
+		err := addRow(ls, &result, markers[lib.GetNowDateString(result.Date)], f)
 		if err != nil {
 			return fmt.Errorf("failed to add row: %v", err.Error())
 		}
@@ -108,13 +135,13 @@ func SyncResultsListStore(results *[]lib.Result, ls *gtk.ListStore) error {
 }
 
 // https://github.com/gotk3/gotk3-examples/blob/master/gtk-examples/treeview/treeview.go
-func GetResultsAsTreeView(results *[]lib.Result, ls *gtk.ListStore) (tv *gtk.TreeView, err error) {
+func GetResultsAsTreeView(results *[]lib.Result, ls *gtk.ListStore, goals []planner.Goal, funds []string) (tv *gtk.TreeView, err error) {
 	tv, err = setupTreeView(ls)
 	if err != nil {
 		return tv, fmt.Errorf("failed to set up tree view: %v", err.Error())
 	}
 
-	err = SyncResultsListStore(results, ls)
+	err = SyncResultsListStore(results, ls, goals, funds)
 	if err != nil {
 		return tv, fmt.Errorf("failed to set up results tree view: %v", err.Error())
 	}
@@ -124,12 +151,16 @@ func GetResultsAsTreeView(results *[]lib.Result, ls *gtk.ListStore) (tv *gtk.Tre
 	return
 }
 
-func GenerateResultsTab(txs *[]lib.TX, results []lib.Result, ls *gtk.ListStore) (grid *gtk.Grid, tabLabel *gtk.Label, err error) {
+func GenerateResultsTab(ws *state.WinState) (grid *gtk.Grid, tabLabel *gtk.Label, err error) {
 	// build the results tab page
-	resultsTreeView, err := GetResultsAsTreeView(&results, ls)
+	resultsTreeView, err := GetResultsAsTreeView(ws.Results, ws.ResultsListStore, ws.Goals, ws.SinkingFunds)
 	if err != nil {
 		return grid, tabLabel, fmt.Errorf("failed to get results as tree view: %v", err.Error())
 	}
+	ws.ResultsTreeView = resultsTreeView
+	SetupColumnPrefs(ws, resultsTreeView, c.ResultsColumns, &ws.Prefs.ResultsColumns)
+	SetupResultsRowMenu(ws)
+
 	resultsTabLabel, err := gtk.LabelNew("Results")
 	if err != nil {
 		return grid, tabLabel, fmt.Errorf("failed to set tab label: %v", err.Error())
@@ -200,6 +231,7 @@ func GetResultsInputs(ws *state.WinState) (*gtk.Entry, *gtk.Entry, *gtk.Entry) {
 		ws.StartDate = fmt.Sprintf("%v-%v-%v", y, m, d)
 		e.SetText(ws.StartDate)
 		UpdateResults(ws, true)
+		refreshConfigOccurrences(ws)
 	}
 
 	endDateInputUpdate := func(e *gtk.Entry) {
@@ -219,6 +251,7 @@ func GetResultsInputs(ws *state.WinState) (*gtk.Entry, *gtk.Entry, *gtk.Entry) {
 		ws.EndDate = fmt.Sprintf("%v-%v-%v", y, m, d)
 		e.SetText(ws.EndDate)
 		UpdateResults(ws, true)
+		refreshConfigOccurrences(ws)
 	}
 
 	updateStartingBalance := func(e *gtk.Entry) {
@@ -254,3 +287,15 @@ func GetResultsInputs(ws *state.WinState) (*gtk.Entry, *gtk.Entry, *gtk.Entry) {
 
 	return startingBalanceInput, stDateInput, endDateInput
 }
+
+// refreshConfigOccurrences updates everything in the config view that depends
+// on the results' date range, such as the occurrence counts, after the range
+// changes.
+func refreshConfigOccurrences(ws *state.WinState) {
+	err := SyncConfigListStore(ws)
+	if err != nil {
+		log.Printf("failed to sync config list store after date range change: %v", err.Error())
+	}
+
+	RefreshDetailPanel(ws)
+}
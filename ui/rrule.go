@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// GetRRuleText formats a saved recurrence rule for a single line of text, such
+// as a config view cell, with its properties separated by spaces.
+func GetRRuleText(rule string) string {
+	return strings.Join(strings.Fields(rule), " ")
+}
+
+// getRRulePreviewText checks a recurrence rule as if it were used for tx, and
+// describes either what's wrong with it, or the next few dates it occurs on.
+// ok is false if the rule can't be applied.
+func getRRulePreviewText(tx *planner.TX, rule string) (text string, ok bool) {
+	if strings.TrimSpace(rule) == "" {
+		return constants.RRuleEditorEmpty, true
+	}
+
+	days, err := planner.GetRRulePreview(tx, rule, time.Now(), constants.RRuleEditorPreviewSize)
+	if err != nil {
+		return err.Error(), false
+	}
+
+	if len(days) == 0 {
+		return constants.RRuleEditorNoDates, true
+	}
+
+	lines := []string{fmt.Sprintf(constants.RRuleEditorValid, len(days))}
+	for _, day := range days {
+		lines = append(lines, day.Format(constants.DetailDateFormat))
+	}
+
+	return strings.Join(lines, "\n"), true
+}
+
+// ShowRRuleEditor shows a dialog for editing the recurrence rule of the TX
+// with the provided ID. The rule is checked as it's typed, and the next few
+// dates it occurs on are previewed; it can only be applied once it's valid.
+func ShowRRuleEditor(ws *state.WinState, id string) {
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("failed to find tx %v to edit its rrule: %v", id, err.Error())
+		return
+	}
+
+	tx := (*ws.TX)[i]
+
+	d, err := gtk.DialogNewWithButtons(
+		fmt.Sprintf("%v - %v", constants.RRuleEditorTitle, tx.Name),
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.RRuleEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.RRuleEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create rrule editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.RRuleEditorWidth, -1)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get rrule editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	help := newDetailLabel(constants.RRuleEditorHelp)
+
+	view, err := gtk.TextViewNew()
+	if err != nil {
+		log.Printf("failed to create rrule editor input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	view.SetMonospace(true)
+	view.SetWrapMode(gtk.WRAP_CHAR)
+
+	buf, err := view.GetBuffer()
+	if err != nil {
+		log.Printf("failed to get rrule editor buffer: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	buf.SetText(tx.RRule)
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create rrule editor scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetSizeRequest(-1, constants.RRuleEditorHeight)
+	sw.Add(view)
+
+	preview := newDetailLabel("")
+	preview.SetSelectable(true)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get rrule editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getRule := func() string {
+		start, end := buf.GetBounds()
+		text, err := buf.GetText(start, end, true)
+		if err != nil {
+			log.Printf("failed to get rrule editor text: %v", err.Error())
+		}
+
+		return text
+	}
+
+	check := func() {
+		text, ok := getRRulePreviewText(&tx, getRule())
+		preview.SetText(text)
+		applyBtn.ToWidget().SetSensitive(ok)
+	}
+
+	buf.Connect(constants.GtkSignalChanged, check)
+	check()
+
+	content.PackStart(help, false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.PackStart(preview, false, false, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	rule := getRule()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK || planner.NormalizeRRule(rule) == tx.RRule {
+		return
+	}
+
+	ConfigChangeByID(ws, id, constants.COLUMN_RRULE, rule)
+}
+
+// EditSelectedRRule shows the recurrence rule editor for the selected TX, if
+// exactly one TX is selected.
+func EditSelectedRRule(ws *state.WinState) {
+	selected := getSelectedTX(ws)
+	if len(selected) != 1 {
+		(*ws.ShowMessageDialog)(constants.MsgSelectOneToEditRRule, gtk.MESSAGE_INFO)
+		return
+	}
+
+	ShowRRuleEditor(ws, selected[0].ID)
+}
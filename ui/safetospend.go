@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// updateSafeToSpendLabel shows how much is safe to spend today in the header
+// bar, with a breakdown of how it was calculated in its tooltip. It's empty
+// if the results end before today.
+func updateSafeToSpendLabel(ws *state.WinState) {
+	if ws.SafeToSpendLabel == nil {
+		return
+	}
+
+	s, ok := planner.GetSafeToSpend(*ws.Results, ws.MinimumBalance, time.Now())
+	if !ok {
+		ws.SafeToSpendLabel.SetText("")
+		ws.SafeToSpendLabel.SetTooltipText("")
+		return
+	}
+
+	ws.SafeToSpendLabel.SetText(s.GetSummary())
+	ws.SafeToSpendLabel.SetTooltipText(s.GetBreakdown())
+}
+
+// GetSafeToSpendLabel creates the "Safe to spend" indicator that is shown in
+// the header bar.
+func GetSafeToSpendLabel(ws *state.WinState) *gtk.Label {
+	l, err := gtk.LabelNew("")
+	if err != nil {
+		log.Fatalf("failed to create safe to spend label: %v", err.Error())
+	}
+
+	ws.SafeToSpendLabel = l
+	updateSafeToSpendLabel(ws)
+
+	return l
+}
+
+// setMinimumBalanceText shows the minimum balance in its input, if it has
+// been created yet.
+func setMinimumBalanceText(ws *state.WinState) {
+	if ws.MinimumBalanceInput == nil {
+		return
+	}
+
+	if ws.MinimumBalance == 0 {
+		ws.MinimumBalanceInput.SetText("")
+		return
+	}
+
+	ws.MinimumBalanceInput.SetText(lib.FormatAsCurrency(ws.MinimumBalance))
+}
+
+// GetMinimumBalanceInput creates the input for the minimum balance that the
+// safe-to-spend amount keeps the projected balance above. An empty input is
+// a minimum of $0.00.
+func GetMinimumBalanceInput(ws *state.WinState) *gtk.Entry {
+	minimumInput, err := gtk.EntryNew()
+	if err != nil {
+		log.Fatal("failed to create minimum balance input entry:", err)
+	}
+
+	updateMinimumBalance := func(e *gtk.Entry) {
+		s, _ := e.GetText()
+		s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+
+		minimum := 0
+		if s != "" {
+			minimum = int(lib.ParseDollarAmount(s, true))
+		}
+
+		changed := minimum != ws.MinimumBalance
+		ws.MinimumBalance = minimum
+		setMinimumBalanceText(ws)
+
+		if changed {
+			UpdateResults(ws, false)
+		}
+	}
+
+	minimumInput.SetPlaceholderText(constants.MinimumInputPlaceholderText)
+	minimumInput.SetTooltipText(constants.MinimumInputTooltip)
+	minimumInput.Connect(constants.GtkSignalActivate, updateMinimumBalance)
+	minimumInput.Connect(constants.GtkSignalFocusOut, updateMinimumBalance)
+
+	SetSpacerMarginsGtkEntry(minimumInput)
+
+	ws.MinimumBalanceInput = minimumInput
+	setMinimumBalanceText(ws)
+
+	return minimumInput
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// ShowSeasonalAmountsEditor shows a dialog with a grid of the twelve monthly
+// amounts of the TX with the provided ID, which starts out with its fixed
+// amount in every month if it doesn't have a seasonal amount table yet. The
+// table can also be removed, which goes back to the fixed amount.
+func ShowSeasonalAmountsEditor(ws *state.WinState, id string) {
+	i, err := planner.GetTXByID(ws.TX, id)
+	if err != nil {
+		log.Printf("failed to find tx %v to edit its seasonal amounts: %v", id, err.Error())
+		return
+	}
+
+	tx := (*ws.TX)[i]
+
+	d, err := gtk.DialogNewWithButtons(
+		fmt.Sprintf("%v - %v", constants.SeasonalEditorTitle, tx.Name),
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.SeasonalEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.SeasonalEditorRemove, gtk.RESPONSE_REJECT},
+		[]interface{}{constants.SeasonalEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create seasonal amounts editor dialog: %v", err.Error())
+		return
+	}
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get seasonal amounts editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create seasonal amounts editor grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetRowSpacing(constants.UISpacer / 2)
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	entries := []*gtk.Entry{}
+
+	for m := 0; m < 12; m++ {
+		entry, err := gtk.EntryNew()
+		if err != nil {
+			log.Printf("failed to create seasonal amounts editor entry: %v", err.Error())
+			d.Destroy()
+			return
+		}
+
+		month := time.Month(m + 1)
+		entry.SetWidthChars(constants.SeasonalEditorEntryWidth)
+		entry.SetText(planner.FormatExceptionAmount(tx.GetSeasonalAmount(time.Date(2000, month, 1, 0, 0, 0, 0, time.UTC))))
+
+		// three columns of four months each, i.e. one season per row
+		row, col := m/constants.SeasonalEditorColumns, m%constants.SeasonalEditorColumns
+		grid.Attach(newDetailLabel(month.String()[:3]), col*2, row, 1, 1)
+		grid.Attach(entry, col*2+1, row, 1, 1)
+		entries = append(entries, entry)
+	}
+
+	status := newDetailLabel("")
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get seasonal amounts editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	removeBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_REJECT)
+	if err != nil {
+		log.Printf("failed to get seasonal amounts editor remove button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	removeBtn.ToWidget().SetSensitive(tx.HasSeasonalAmounts())
+
+	getText := func() string {
+		values := []string{}
+		for _, entry := range entries {
+			text, _ := entry.GetText()
+			values = append(values, text)
+		}
+
+		return strings.Join(values, constants.ExceptionSeparator+" ")
+	}
+
+	check := func() {
+		amounts, err := planner.ParseSeasonalAmounts(getText())
+		applyBtn.ToWidget().SetSensitive(err == nil)
+
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+
+		preview := tx
+		preview.SeasonalAmounts = amounts
+		lo, hi := preview.GetSeasonalRange()
+		total := 0
+		for _, a := range amounts {
+			total += a
+		}
+
+		status.SetText(fmt.Sprintf(constants.SeasonalEditorSummary, lib.FormatAsCurrency(lo), lib.FormatAsCurrency(hi), lib.FormatAsCurrency(total)))
+	}
+
+	for _, entry := range entries {
+		entry.Connect(constants.GtkSignalChanged, check)
+	}
+
+	check()
+
+	content.PackStart(newDetailLabel(constants.SeasonalEditorHelp), false, false, 0)
+	content.PackStart(grid, true, true, 0)
+	content.PackStart(status, false, false, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	text := getText()
+	d.Destroy()
+
+	switch resp {
+	case gtk.RESPONSE_OK:
+		ConfigChangeByID(ws, id, constants.COLUMN_SEASONAL, text)
+	case gtk.RESPONSE_REJECT:
+		ConfigChangeByID(ws, id, constants.COLUMN_SEASONAL, "")
+	}
+}
+
+// EditSelectedSeasonalAmounts shows the seasonal amounts editor for the
+// selected TX, if exactly one TX is selected.
+func EditSelectedSeasonalAmounts(ws *state.WinState) {
+	selected := getSelectedTX(ws)
+	if len(selected) != 1 {
+		(*ws.ShowMessageDialog)(constants.MsgSelectOneToEditSeasonal, gtk.MESSAGE_INFO)
+		return
+	}
+
+	ShowSeasonalAmountsEditor(ws, selected[0].ID)
+}
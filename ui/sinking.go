@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// syncSinkingFundsListStore fills the list store with each bill's sinking
+// fund, with a string for each of constants.SinkingFundColumns.
+func syncSinkingFundsListStore(ls *gtk.ListStore, funds []planner.SinkingFund) {
+	ls.Clear()
+
+	columns := []int{}
+	for i := range constants.SinkingFundColumns {
+		columns = append(columns, i)
+	}
+
+	for _, f := range funds {
+		status := constants.SinkingFundsNotFunded
+		if f.Funded {
+			status = constants.SinkingFundsFunded
+		}
+
+		err := ls.Set(ls.Append(), columns, []interface{}{
+			glib.MarkupEscapeText(f.Bill.Name),
+			f.NextDue.Format(constants.DetailDateFormat),
+			lib.FormatAsCurrency(f.Amount),
+			strconv.Itoa(f.Transfers),
+			lib.FormatAsCurrency(f.Monthly),
+			lib.FormatAsCurrency(f.Steady),
+			status,
+		})
+		if err != nil {
+			log.Printf("failed to add sinking fund row: %v", err.Error())
+		}
+	}
+}
+
+// ShowSinkingFundCalculator shows a dialog that calculates how much to set
+// aside each month for each bill that recurs less often than every month
+// (see planner.GetSinkingFunds), starting from a chosen first transfer. The
+// transfers into (and withdrawals from) each fund that isn't already funded
+// can then be added as transactions.
+func ShowSinkingFundCalculator(ws *state.WinState) {
+	now := time.Now()
+
+	d, err := gtk.DialogNewWithButtons(
+		constants.SinkingFundsTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.SinkingFundsCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.SinkingFundsAdd, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create sinking funds dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.SinkingFundsWidth, constants.SinkingFundsHeight)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get sinking funds content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	grid, err := gtk.GridNew()
+	if err != nil {
+		log.Printf("failed to create sinking funds grid: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	grid.SetColumnSpacing(constants.UISpacer)
+
+	start, err := gtk.EntryNew()
+	if err != nil {
+		log.Printf("failed to create sinking funds start input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	start.SetText(lib.GetNowDateString(time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)))
+
+	grid.Attach(newDetailLabel(constants.SinkingFundsStart), 0, 0, 1, 1)
+	grid.Attach(start, 1, 0, 1, 1)
+
+	summary := newDetailLabel("")
+
+	types := []glib.Type{}
+	for range constants.SinkingFundColumns {
+		types = append(types, glib.TYPE_STRING)
+	}
+
+	ls, err := gtk.ListStoreNew(types...)
+	if err != nil {
+		log.Printf("failed to create sinking funds list store: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	tv, err := gtk.TreeViewNewWithModel(ls)
+	if err != nil {
+		log.Printf("failed to create sinking funds tree view: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	for i, column := range constants.SinkingFundColumns {
+		tvc, err := createColumn(column, i)
+		if err != nil {
+			log.Printf("failed to create sinking funds column %v: %v", column, err.Error())
+			d.Destroy()
+			return
+		}
+
+		tv.AppendColumn(tvc)
+	}
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create sinking funds scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetVExpand(true)
+	sw.Add(tv)
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get sinking funds add button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	var funds []planner.SinkingFund
+	var first time.Time
+
+	check := func() {
+		funds = nil
+		unfunded := 0
+		defer func() {
+			applyBtn.ToWidget().SetSensitive(unfunded > 0)
+			syncSinkingFundsListStore(ls, funds)
+		}()
+
+		s, _ := start.GetText()
+		day, err := planner.ParseFirstPaymentDate(s)
+		if err != nil {
+			summary.SetText(err.Error())
+			return
+		}
+
+		first = day
+
+		funds = planner.GetSinkingFunds(*ws.TX, lib.GetDateFromStrSafe(ws.StartDate, now), first, ws.Holidays)
+		if len(funds) == 0 {
+			summary.SetText(constants.SinkingFundsNone)
+			return
+		}
+
+		monthly := 0
+		for _, f := range funds {
+			monthly += f.Monthly
+			if !f.Funded {
+				unfunded++
+			}
+		}
+
+		summary.SetText(fmt.Sprintf(constants.SinkingFundsSummary, lib.FormatAsCurrency(monthly), len(funds)))
+	}
+
+	start.Connect(constants.GtkSignalChanged, check)
+
+	content.PackStart(newDetailLabel(constants.SinkingFundsHelp), false, false, 0)
+	content.PackStart(grid, false, false, 0)
+	content.PackStart(summary, false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.ShowAll()
+
+	check()
+
+	resp := d.Run()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK || len(funds) == 0 {
+		return
+	}
+
+	*ws.TX = append(*ws.TX, planner.GetSinkingFundTXs(funds, first, now, *ws.TX)...)
+
+	// new conf items are added at the bottom, as in AddConfItem
+	SetConfigScrollPosition(ws, 65535, -1)
+	ClearAllSelections(ws)
+	UpdateResults(ws, false)
+	SyncConfigListStore(ws)
+	RestoreConfigScrollPosition(ws)
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// ShowSweepsEditor shows a dialog for editing the plan's sweep rules, with one
+// per line (see planner.ParseSweepRules). The rules are checked as they're
+// typed, and can only be applied once they're valid, after which the results
+// are recalculated.
+func ShowSweepsEditor(ws *state.WinState) {
+	d, err := gtk.DialogNewWithButtons(
+		constants.SweepsEditorTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.SweepsEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.SweepsEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create sweep rules editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.SweepsEditorWidth, -1)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get sweep rules editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	view, err := gtk.TextViewNew()
+	if err != nil {
+		log.Printf("failed to create sweep rules editor input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	view.SetMonospace(true)
+
+	buf, err := view.GetBuffer()
+	if err != nil {
+		log.Printf("failed to get sweep rules editor buffer: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	buf.SetText(planner.GetSweepRulesText(ws.Sweeps))
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create sweep rules editor scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetSizeRequest(-1, constants.SweepsEditorHeight)
+	sw.Add(view)
+
+	status := newDetailLabel("")
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get sweep rules editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getText := func() string {
+		start, end := buf.GetBounds()
+		text, err := buf.GetText(start, end, true)
+		if err != nil {
+			log.Printf("failed to get sweep rules editor text: %v", err.Error())
+		}
+
+		return text
+	}
+
+	check := func() {
+		rules, err := planner.ParseSweepRules(getText())
+		applyBtn.ToWidget().SetSensitive(err == nil)
+
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+
+		status.SetText(fmt.Sprintf(constants.SweepsEditorValid, len(rules)))
+	}
+
+	buf.Connect(constants.GtkSignalChanged, check)
+	check()
+
+	content.PackStart(newDetailLabel(constants.SweepsEditorHelp), false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.PackStart(status, false, false, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	text := getText()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK {
+		return
+	}
+
+	rules, err := planner.ParseSweepRules(text)
+	if err != nil {
+		log.Printf("failed to parse sweep rules: %v", err.Error())
+		return
+	}
+
+	if planner.GetSweepRulesText(rules) == planner.GetSweepRulesText(ws.Sweeps) {
+		return
+	}
+
+	ws.Sweeps = rules
+	UpdateResults(ws, false)
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// UpdateTotalsLabel recalculates the recurring totals footer from the rows that
+// are currently shown in the config tree view.
+func UpdateTotalsLabel(ws *state.WinState) {
+	if ws.TotalsLabel == nil || ws.ConfigListStore == nil {
+		return
+	}
+
+	byID := make(map[string]*planner.TX, len(*ws.TX))
+	for i := range *ws.TX {
+		byID[(*ws.TX)[i].ID] = &(*ws.TX)[i]
+	}
+
+	visible := []planner.TX{}
+	for _, id := range GetConfigListStoreIDs(ws) {
+		if tx, ok := byID[id]; ok {
+			visible = append(visible, *tx)
+		}
+	}
+
+	t := planner.GetRecurringTotals(visible, time.Now())
+
+	ws.TotalsLabel.SetText(fmt.Sprintf(
+		constants.TotalsLabelFormat,
+		lib.FormatAsCurrency(t.MonthlyIncome()),
+		lib.FormatAsCurrency(t.MonthlyExpenses()),
+		lib.FormatAsCurrency(t.MonthlyNet()),
+		lib.FormatAsCurrency(t.YearlyIncome),
+		lib.FormatAsCurrency(t.YearlyExpenses),
+		lib.FormatAsCurrency(t.YearlyNet()),
+	))
+}
+
+// GetTotalsLabel creates the footer beneath the config tree view that shows
+// the recurring income, expenses and net per month and per year.
+func GetTotalsLabel(ws *state.WinState) *gtk.Label {
+	l, err := gtk.LabelNew("")
+	if err != nil {
+		log.Fatalf("failed to create totals label: %v", err.Error())
+	}
+
+	l.SetTooltipText(constants.TotalsLabelTooltip)
+	l.SetSelectable(true)
+	l.SetHAlign(gtk.ALIGN_START)
+	l.SetMarginStart(constants.UISpacer)
+	l.SetMarginEnd(constants.UISpacer)
+
+	ws.TotalsLabel = l
+	UpdateTotalsLabel(ws)
+
+	return l
+}
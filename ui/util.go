@@ -7,8 +7,10 @@ import (
 
 	lib "github.com/charles-m-knox/finance-planner-lib"
 	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
 	"github.com/charles-m-knox/gtk-finance-planner/state"
 
+	"github.com/gotk3/gotk3/gdk"
 	"github.com/gotk3/gotk3/gtk"
 )
 
@@ -66,21 +68,31 @@ func UpdateResults(ws *state.WinState, switchTo bool) {
 
 	now := time.Now()
 
-	*ws.Results, err = lib.GetResults(
+	*ws.Results, err = planner.GetResults(
 		*ws.TX,
 		lib.GetDateFromStrSafe(ws.StartDate, now),
 		lib.GetDateFromStrSafe(ws.EndDate, now),
 		ws.StartingBalance,
-		func(_ string) {},
+		ws.Holidays,
+		ws.Sweeps,
+		ws.Interest,
 	)
 	if err != nil {
 		log.Fatal("failed to generate results from date strings", err.Error())
 	}
 
+	ws.SinkingFunds = planner.GetSinkingFundBalances(
+		*ws.TX,
+		lib.GetDateFromStrSafe(ws.StartDate, now),
+		lib.GetDateFromStrSafe(ws.EndDate, now),
+		ws.Holidays,
+	)
+
 	ws.Header.SetSubtitle(fmt.Sprintf("%v*", ws.OpenFileName))
+	updateSafeToSpendLabel(ws)
 
 	if ws.ResultsListStore != nil {
-		err = SyncResultsListStore(ws.Results, ws.ResultsListStore)
+		err = SyncResultsListStore(ws.Results, ws.ResultsListStore, ws.Goals, ws.SinkingFunds)
 		if err != nil {
 			log.Print("failed to sync results list store:", err.Error())
 		}
@@ -90,3 +102,22 @@ func UpdateResults(ws *state.WinState, switchTo bool) {
 		}
 	}
 }
+
+// IsControlHeld returns true if the control key is currently being held down.
+// This is useful for signals such as "clicked" that don't provide the state of
+// the keyboard modifiers.
+func IsControlHeld() bool {
+	display, err := gdk.DisplayGetDefault()
+	if err != nil {
+		log.Printf("failed to get default display: %v", err.Error())
+		return false
+	}
+
+	keymap, err := display.GetKeymap()
+	if err != nil {
+		log.Printf("failed to get keymap: %v", err.Error())
+		return false
+	}
+
+	return keymap.GetModifierState()&uint(gdk.CONTROL_MASK) != 0
+}
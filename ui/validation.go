@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/oldutil"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	lib "github.com/charles-m-knox/finance-planner-lib"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// maxProblemsInTooltip limits how many problems are listed in the tooltip of
+// the header bar's problem indicator.
+const maxProblemsInTooltip = 20
+
+// addProblemHighlight makes the cells of a config column tinted whenever
+// their TX has a validation problem in that column.
+func addProblemHighlight(tvc *gtk.TreeViewColumn, r *gtk.CellRenderer, column int) {
+	r.SetProperty("cell-background", constants.ProblemCellBackground)
+	tvc.AddAttribute(r, "cell-background-set", constants.COLUMN_PROBLEMS+column)
+}
+
+// ValidateConfig checks every TX for problems, counts how many times each TX
+// occurs in the results' date range, and updates the problem indicator in the
+// header bar. The config list store should be updated afterwards so that the
+// affected cells are highlighted.
+func ValidateConfig(ws *state.WinState) {
+	now := time.Now()
+
+	// formulas and linked amounts may depend on something that has changed
+	planner.ApplyFormulas(*ws.TX, ws.Variables)
+	planner.LinkAmounts(*ws.TX)
+
+	occurrences, lintProblems := planner.Lint(
+		*ws.TX,
+		lib.GetDateFromStrSafe(ws.StartDate, now),
+		lib.GetDateFromStrSafe(ws.EndDate, now),
+		ws.Holidays,
+	)
+
+	ws.Occurrences = occurrences
+	ws.Problems = append(planner.Validate(*ws.TX, ws.InputProblems), lintProblems...)
+	ws.Problems = append(ws.Problems, planner.GetFormulaProblems(*ws.TX, ws.Variables)...)
+	ws.ProblemCells = ws.Problems.ByCell()
+
+	updateProblemsLabel(ws)
+}
+
+func updateProblemsLabel(ws *state.WinState) {
+	if ws.ProblemsLabel == nil {
+		return
+	}
+
+	n := len(ws.Problems)
+
+	switch n {
+	case 0:
+		ws.ProblemsLabel.SetText("")
+		ws.ProblemsLabel.SetTooltipText("")
+		return
+	case 1:
+		ws.ProblemsLabel.SetText(constants.ProblemsLabelSingular)
+	default:
+		ws.ProblemsLabel.SetText(fmt.Sprintf(constants.ProblemsLabelPlural, n))
+	}
+
+	names := make(map[string]string, len(*ws.TX))
+	for i := range *ws.TX {
+		names[(*ws.TX)[i].ID] = (*ws.TX)[i].Name
+	}
+
+	lines := []string{}
+	for i, problem := range ws.Problems {
+		if i >= maxProblemsInTooltip {
+			lines = append(lines, fmt.Sprintf("...and %v more", n-i))
+			break
+		}
+
+		lines = append(lines, fmt.Sprintf("%v: %v", names[problem.TXID], problem.Message))
+	}
+
+	ws.ProblemsLabel.SetTooltipText(strings.Join(lines, "\n"))
+}
+
+// GetProblemsLabel creates the "N problems" indicator that is shown in the
+// header bar whenever any TX has a validation problem.
+func GetProblemsLabel(ws *state.WinState) *gtk.Label {
+	l, err := gtk.LabelNew("")
+	if err != nil {
+		log.Fatalf("failed to create problems label: %v", err.Error())
+	}
+
+	ws.ProblemsLabel = l
+	updateProblemsLabel(ws)
+
+	return l
+}
+
+// configQueryTooltip shows the reasons for a highlighted config cell when the
+// user hovers over it.
+func configQueryTooltip(ws *state.WinState) func(*gtk.TreeView, int, int, bool, *gtk.Tooltip) bool {
+	return func(tv *gtk.TreeView, x, y int, keyboard bool, tooltip *gtk.Tooltip) bool {
+		if keyboard {
+			return false
+		}
+
+		var bx, by int
+		tv.ConvertWidgetToBinWindowCoords(x, y, &bx, &by)
+
+		path, col, _, _, ok := tv.GetPathAtPos(bx, by)
+		if !ok || path == nil || col == nil {
+			return false
+		}
+
+		column := ""
+		for id, c := range ws.ConfigColumns {
+			if c.Native() == col.Native() {
+				column = constants.ConfigColumns[id]
+				break
+			}
+		}
+
+		if column == "" {
+			return false
+		}
+
+		id, err := oldutil.GetTXIDByListStorePath(ws.ConfigListStore, path)
+		if err != nil || id == "" {
+			return false
+		}
+
+		messages := ws.ProblemCells[id][column]
+		if len(messages) == 0 {
+			return false
+		}
+
+		tooltip.SetText(strings.Join(messages, "\n"))
+		tv.SetTooltipCell(tooltip, path, col, nil)
+
+		return true
+	}
+}
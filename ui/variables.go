@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/charles-m-knox/gtk-finance-planner/constants"
+	"github.com/charles-m-knox/gtk-finance-planner/planner"
+	"github.com/charles-m-knox/gtk-finance-planner/state"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// ShowVariablesEditor shows a dialog for editing the plan's variables, with
+// one per line (see planner.ParseVariables). The variables are checked as
+// they're typed, and can only be applied once they're valid, after which
+// every formula is recalculated.
+func ShowVariablesEditor(ws *state.WinState) {
+	d, err := gtk.DialogNewWithButtons(
+		constants.VariablesEditorTitle,
+		ws.Win,
+		gtk.DIALOG_MODAL|gtk.DIALOG_DESTROY_WITH_PARENT,
+		[]interface{}{constants.VariablesEditorCancel, gtk.RESPONSE_CANCEL},
+		[]interface{}{constants.VariablesEditorApply, gtk.RESPONSE_OK},
+	)
+	if err != nil {
+		log.Printf("failed to create variables editor dialog: %v", err.Error())
+		return
+	}
+
+	d.SetDefaultSize(constants.VariablesEditorWidth, -1)
+
+	content, err := d.GetContentArea()
+	if err != nil {
+		log.Printf("failed to get variables editor content area: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	content.SetSpacing(constants.UISpacer)
+	content.SetMarginStart(constants.UISpacer)
+	content.SetMarginEnd(constants.UISpacer)
+	content.SetMarginTop(constants.UISpacer)
+
+	view, err := gtk.TextViewNew()
+	if err != nil {
+		log.Printf("failed to create variables editor input: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	view.SetMonospace(true)
+
+	buf, err := view.GetBuffer()
+	if err != nil {
+		log.Printf("failed to get variables editor buffer: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	buf.SetText(planner.GetVariablesText(ws.Variables))
+
+	sw, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		log.Printf("failed to create variables editor scrolled window: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	sw.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	sw.SetShadowType(gtk.SHADOW_IN)
+	sw.SetSizeRequest(-1, constants.VariablesEditorHeight)
+	sw.Add(view)
+
+	status := newDetailLabel("")
+
+	applyBtn, err := d.GetWidgetForResponse(gtk.RESPONSE_OK)
+	if err != nil {
+		log.Printf("failed to get variables editor apply button: %v", err.Error())
+		d.Destroy()
+		return
+	}
+
+	getText := func() string {
+		start, end := buf.GetBounds()
+		text, err := buf.GetText(start, end, true)
+		if err != nil {
+			log.Printf("failed to get variables editor text: %v", err.Error())
+		}
+
+		return text
+	}
+
+	formulas := 0
+	for _, tx := range *ws.TX {
+		if tx.Formula != "" {
+			formulas++
+		}
+	}
+
+	check := func() {
+		vars, err := planner.ParseVariables(getText())
+		applyBtn.ToWidget().SetSensitive(err == nil)
+
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+
+		status.SetText(fmt.Sprintf(constants.VariablesEditorValid, len(vars), formulas))
+	}
+
+	buf.Connect(constants.GtkSignalChanged, check)
+	check()
+
+	content.PackStart(newDetailLabel(constants.VariablesEditorHelp), false, false, 0)
+	content.PackStart(sw, true, true, 0)
+	content.PackStart(status, false, false, 0)
+	content.ShowAll()
+
+	resp := d.Run()
+	text := getText()
+	d.Destroy()
+
+	if resp != gtk.RESPONSE_OK {
+		return
+	}
+
+	vars, err := planner.ParseVariables(text)
+	if err != nil {
+		log.Printf("failed to parse variables: %v", err.Error())
+		return
+	}
+
+	if planner.GetVariablesText(vars) == planner.GetVariablesText(ws.Variables) {
+		return
+	}
+
+	ws.Variables = vars
+
+	// SyncConfigListStore recalculates the formulas while validating
+	err = SyncConfigListStore(ws)
+	if err != nil {
+		log.Printf("failed to sync config list store after variables change: %v", err.Error())
+	}
+
+	UpdateResults(ws, false)
+	RefreshDetailPanel(ws)
+}